@@ -5,3 +5,9 @@ import "errors"
 var ErrVectorIndexOutOfRange = errors.New("vector index out of range")
 var ErrVectorTooLarge = errors.New("vector to large")
 var ErrIterationOutOfRange = errors.New("iteration out of range")
+var ErrUnhashable = errors.New("value cannot be stored in a hashed collection")
+var ErrIndexOutOfRange = errors.New("index out of range")
+var ErrEmptyCollection = errors.New("collection is empty")
+var ErrIncomparable = errors.New("values cannot be compared")
+var ErrZeroStep = errors.New("range step cannot be zero")
+var ErrNotARune = errors.New("string sequences can only hold runes")
@@ -0,0 +1,81 @@
+package collections
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// A Hashable is a value that defines its own hash code and equality,
+// and so can be stored in hashed collections like HashMap and HashSet.
+// Values that are equal must have the same hash code
+type Hashable interface {
+	Hash() uint32
+	Equals(other interface{}) bool
+}
+
+// Computes the hash code of a value stored in a hashed collection.
+// Hashed collections support strings, numbers, bools, nil and
+// Hashable values. Any other value panics with ErrUnhashable
+func Hash(value interface{}) uint32 {
+	switch v := value.(type) {
+	case Hashable:
+		return v.Hash()
+	case nil:
+		return 0
+	case string:
+		hash := fnv.New32a()
+		hash.Write([]byte(v))
+		return hash.Sum32()
+	case bool:
+		if v {
+			return 1
+		}
+		return 2
+	case int:
+		return hashUint64(uint64(v))
+	case int8:
+		return hashUint64(uint64(v))
+	case int16:
+		return hashUint64(uint64(v))
+	case int32:
+		return hashUint64(uint64(v))
+	case int64:
+		return hashUint64(uint64(v))
+	case uint:
+		return hashUint64(uint64(v))
+	case uint8:
+		return hashUint64(uint64(v))
+	case uint16:
+		return hashUint64(uint64(v))
+	case uint32:
+		return hashUint64(uint64(v))
+	case uint64:
+		return hashUint64(v)
+	case uintptr:
+		return hashUint64(uint64(v))
+	case float32:
+		return hashUint64(math.Float64bits(float64(v)))
+	case float64:
+		return hashUint64(math.Float64bits(v))
+	}
+	panic(ErrUnhashable)
+}
+
+// Mixes the bits of a 64 bit integer into a 32 bit hash code, so
+// that small integers don't all land in the same branch of a trie
+func hashUint64(x uint64) uint32 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return uint32(x)
+}
+
+// Tests whether two values stored in a hashed collection are equal
+func Equal(left interface{}, right interface{}) bool {
+	if hashable, ok := left.(Hashable); ok {
+		return hashable.Equals(right)
+	}
+	return left == right
+}
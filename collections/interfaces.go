@@ -33,8 +33,12 @@ type Sequence interface {
 	Get(index int) interface{}
 	Update(index int, value interface{}) Sequence
 	Append(value interface{}) Sequence
-	//Prepend(value interface{}) Sequence
-	//Slice(start int, end int) Sequence
+	Prepend(value interface{}) Sequence
+	// Returns the elements from start up to, but not including, end
+	Slice(start int, end int) Sequence
+	// Returns the elements of this sequence followed by the
+	// elements of other
+	Concat(other Sequence) Sequence
 }
 
 // A Set is an immutable iterable with no duplicates. Sets support standard
@@ -29,7 +29,7 @@ func (iterator *SequenceIterator) MoveNext() bool {
 }
 
 func (iterator *SequenceIterator) Current() interface{} {
-	if iterator.index < iterator.sequence.Size() {
+	if iterator.index < 0 || iterator.index >= iterator.sequence.Size() {
 		panic(ErrIterationOutOfRange)
 	}
 	return iterator.sequence.Get(iterator.index)
@@ -1 +1,175 @@
 package collections
+
+// LinkedList is a persistent singly linked (cons) list. Lists that
+// share a tail share its nodes, so you can expect:
+// Prepend, Head and Tail (O(1))
+// Random access, functional update and append (O(n))
+
+type LinkedList struct {
+	head interface{}
+	tail *LinkedList
+	// The number of elements in the list. Zero for the empty list
+	size int
+}
+
+var emptyLinkedList = &LinkedList{}
+
+func EmptyLinkedList() *LinkedList {
+	return emptyLinkedList
+}
+
+func NewLinkedList(values ...interface{}) *LinkedList {
+	list := EmptyLinkedList()
+	for i := len(values) - 1; i >= 0; i-- {
+		list = list.Cons(values[i])
+	}
+	return list
+}
+
+// Returns a new list with value as its head and this list as its tail
+func (list *LinkedList) Cons(value interface{}) *LinkedList {
+	return &LinkedList{
+		head: value,
+		tail: list,
+		size: list.size + 1,
+	}
+}
+
+func (list *LinkedList) Head() interface{} {
+	if list.size == 0 {
+		panic(ErrEmptyCollection)
+	}
+	return list.head
+}
+
+func (list *LinkedList) Tail() *LinkedList {
+	if list.size == 0 {
+		panic(ErrEmptyCollection)
+	}
+	return list.tail
+}
+
+func (list *LinkedList) Size() int {
+	return list.size
+}
+
+func (list *LinkedList) nodeAt(index int) *LinkedList {
+	if index < 0 || index >= list.size {
+		panic(ErrIndexOutOfRange)
+	}
+	node := list
+	for i := 0; i < index; i++ {
+		node = node.tail
+	}
+	return node
+}
+
+func (list *LinkedList) Get(index int) interface{} {
+	return list.nodeAt(index).head
+}
+
+// Rebuilds the first count elements of the list on top of a new tail
+func (list *LinkedList) withTail(count int, tail *LinkedList) *LinkedList {
+	prefix := make([]interface{}, 0, count)
+	node := list
+	for i := 0; i < count; i++ {
+		prefix = append(prefix, node.head)
+		node = node.tail
+	}
+	for i := len(prefix) - 1; i >= 0; i-- {
+		tail = tail.Cons(prefix[i])
+	}
+	return tail
+}
+
+// Copies the elements before index and shares the rest of the list
+func (list *LinkedList) Update(index int, value interface{}) Sequence {
+	node := list.nodeAt(index)
+	return list.withTail(index, node.tail.Cons(value))
+}
+
+// Copies the whole list
+func (list *LinkedList) Append(value interface{}) Sequence {
+	return list.withTail(list.size, EmptyLinkedList().Cons(value))
+}
+
+func (list *LinkedList) Prepend(value interface{}) Sequence {
+	return list.Cons(value)
+}
+
+// Shares the original list's nodes when the slice runs to the
+// end of the list, and otherwise copies the sliced elements
+func (list *LinkedList) Slice(start int, end int) Sequence {
+	if start < 0 || end > list.size || start > end {
+		panic(ErrIndexOutOfRange)
+	}
+	node := list
+	for i := 0; i < start; i++ {
+		node = node.tail
+	}
+	if end == list.size {
+		return node
+	}
+	return node.withTail(end-start, EmptyLinkedList())
+}
+
+// Copies this list and shares other if it is also a LinkedList
+func (list *LinkedList) Concat(other Sequence) Sequence {
+	otherList, ok := other.(*LinkedList)
+	if !ok {
+		otherList = EmptyLinkedList()
+		for i := other.Size() - 1; i >= 0; i-- {
+			otherList = otherList.Cons(other.Get(i))
+		}
+	}
+	return list.withTail(list.size, otherList)
+}
+
+type linkedListIterator struct {
+	next    *LinkedList
+	current interface{}
+	started bool
+	done    bool
+}
+
+func (iterator *linkedListIterator) MoveNext() bool {
+	iterator.started = true
+	if iterator.next.size == 0 {
+		iterator.done = true
+		return false
+	}
+	iterator.current = iterator.next.head
+	iterator.next = iterator.next.tail
+	return true
+}
+
+func (iterator *linkedListIterator) Current() interface{} {
+	if !iterator.started || iterator.done {
+		panic(ErrIterationOutOfRange)
+	}
+	return iterator.current
+}
+
+func (list *LinkedList) Iterator() Iterator {
+	return &linkedListIterator{next: list}
+}
+
+func (list *LinkedList) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(list, mapFn)
+}
+
+func (list *LinkedList) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(list, filterFn)
+}
+
+func (list *LinkedList) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(list, initialValue, reducerFn)
+}
+
+func (list *LinkedList) ForEach(iterFn func(interface{})) {
+	forEachHelper(list, iterFn)
+}
+
+func (list *LinkedList) ToSlice() []interface{} {
+	return toSliceHelper(list)
+}
@@ -0,0 +1,88 @@
+package collections
+
+import "testing"
+
+func checkSequenceContents(t *testing.T, sequence Sequence, expected []interface{}) {
+	t.Helper()
+	if sequence.Size() != len(expected) {
+		t.Fatalf("expected size %v, got %v", len(expected), sequence.Size())
+	}
+	for i, value := range expected {
+		if sequence.Get(i) != value {
+			t.Fatalf("expected %v at index %v, got %v", value, i, sequence.Get(i))
+		}
+	}
+}
+
+func TestEmptyLinkedList(t *testing.T) {
+	list := EmptyLinkedList()
+	if list.Size() != 0 {
+		t.Fatalf("expected empty list, got size %v", list.Size())
+	}
+	shouldPanic(t, func() { list.Head() }, ErrEmptyCollection)
+	shouldPanic(t, func() { list.Tail() }, ErrEmptyCollection)
+	shouldPanic(t, func() { list.Get(0) }, ErrIndexOutOfRange)
+	if list.Iterator().MoveNext() {
+		t.Fatalf("empty list should have nothing to iterate")
+	}
+}
+
+func TestLinkedListHeadAndTail(t *testing.T) {
+	list := NewLinkedList(1, 2, 3)
+	if list.Head() != 1 || list.Tail().Head() != 2 || list.Tail().Size() != 2 {
+		t.Fatalf("unexpected head and tail")
+	}
+	checkSequenceContents(t, list, []interface{}{1, 2, 3})
+}
+
+func TestLinkedListIsPersistent(t *testing.T) {
+	original := NewLinkedList(1, 2, 3)
+	updated := original.Update(1, 20)
+	appended := original.Append(4)
+	prepended := original.Prepend(0)
+	checkSequenceContents(t, original, []interface{}{1, 2, 3})
+	checkSequenceContents(t, updated, []interface{}{1, 20, 3})
+	checkSequenceContents(t, appended, []interface{}{1, 2, 3, 4})
+	checkSequenceContents(t, prepended, []interface{}{0, 1, 2, 3})
+	if prepended.(*LinkedList).Tail() != original {
+		t.Fatalf("prepend should share the original list")
+	}
+	if updated.(*LinkedList).Tail().Tail() != original.Tail().Tail() {
+		t.Fatalf("update should share the list after the updated element")
+	}
+}
+
+func TestLinkedListSliceAndConcat(t *testing.T) {
+	list := NewLinkedList(0, 1, 2, 3, 4)
+	checkSequenceContents(t, list.Slice(1, 3), []interface{}{1, 2})
+	checkSequenceContents(t, list.Slice(2, 2), []interface{}{})
+	if list.Slice(3, 5) != list.Tail().Tail().Tail() {
+		t.Fatalf("slicing to the end should share the original list")
+	}
+	shouldPanic(t, func() { list.Slice(3, 6) }, ErrIndexOutOfRange)
+	shouldPanic(t, func() { list.Slice(3, 2) }, ErrIndexOutOfRange)
+
+	other := NewLinkedList(5, 6)
+	concatenated := list.Concat(other)
+	checkSequenceContents(t, concatenated, []interface{}{0, 1, 2, 3, 4, 5, 6})
+	checkSequenceContents(t, list.Concat(NewVector(5, 6)), []interface{}{0, 1, 2, 3, 4, 5, 6})
+}
+
+func TestLinkedListIteration(t *testing.T) {
+	list := NewLinkedList(1, 2, 3, 4)
+	sum := list.Fold(0, func(total interface{}, value interface{}) interface{} {
+		return total.(int) + value.(int)
+	})
+	if sum != 10 {
+		t.Fatalf("expected sum 10, got %v", sum)
+	}
+	evens := list.Filter(func(value interface{}) bool { return value.(int)%2 == 0 }).ToSlice()
+	if len(evens) != 2 || evens[0] != 2 || evens[1] != 4 {
+		t.Fatalf("unexpected filter result %v", evens)
+	}
+	iterator := list.Iterator()
+	shouldPanic(t, func() { iterator.Current() }, ErrIterationOutOfRange)
+	for iterator.MoveNext() {
+	}
+	shouldPanic(t, func() { iterator.Current() }, ErrIterationOutOfRange)
+}
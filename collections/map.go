@@ -1 +1,426 @@
 package collections
+
+// Aliased because vector.go declares a bits constant
+import mathbits "math/bits"
+
+// HashMap is a persistent hash array mapped trie (HAMT), based on
+// Phil Bagwell's "Ideal Hash Trees" and the implementations used by
+// Clojure and Scala. Each level of the trie consumes 5 bits of a key's
+// hash, so a map of n elements is about log32(n) levels deep. You can
+// expect:
+// Lookup, insertion and removal (effectively O(1), strictly O(log32 n))
+// Iteration (O(n))
+//
+// Updates copy only the path from the root to the changed entry, so
+// different versions of a map share almost all of their structure.
+//
+// Keys must be supported by Hash, and are compared with Equal
+
+const hamtBits = 5
+const hamtMask = 31
+const hamtHashBits = 32
+
+// A MapEntry is a key-value pair. Iterating over a Map
+// produces MapEntries
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+type hamtNode interface {
+	get(key interface{}, hash uint32, shift uint) (interface{}, bool)
+	// Returns the updated node and whether the key was newly added
+	set(key interface{}, value interface{}, hash uint32, shift uint) (hamtNode, bool)
+	// Returns the updated node, which is nil if the node is now
+	// empty, and whether the key was present
+	remove(key interface{}, hash uint32, shift uint) (hamtNode, bool)
+}
+
+// A slot in a bitmapNode. A slot either holds a single entry
+// or, if node is not nil, a subtrie
+type hamtSlot struct {
+	hash  uint32
+	key   interface{}
+	value interface{}
+	node  hamtNode
+}
+
+// A bitmapNode stores only its occupied slots. Bit i of the bitmap is
+// set if the slot for the 5 bit hash fragment i is occupied, and the
+// slot's position in slots is the number of set bits below bit i
+type bitmapNode struct {
+	bitmap uint32
+	slots  []hamtSlot
+}
+
+// A collisionNode holds entries whose keys have identical hashes
+type collisionNode struct {
+	hash    uint32
+	entries []MapEntry
+}
+
+var emptyBitmapNode = &bitmapNode{}
+
+func fragment(hash uint32, shift uint) uint32 {
+	return (hash >> shift) & hamtMask
+}
+
+func (node *bitmapNode) position(bit uint32) int {
+	return mathbits.OnesCount32(node.bitmap & (bit - 1))
+}
+
+func (node *bitmapNode) get(key interface{}, hash uint32, shift uint) (interface{}, bool) {
+	bit := uint32(1) << fragment(hash, shift)
+	if node.bitmap&bit == 0 {
+		return nil, false
+	}
+	slot := node.slots[node.position(bit)]
+	if slot.node != nil {
+		return slot.node.get(key, hash, shift+hamtBits)
+	}
+	if slot.hash == hash && Equal(slot.key, key) {
+		return slot.value, true
+	}
+	return nil, false
+}
+
+func (node *bitmapNode) withSlot(position int, slot hamtSlot) *bitmapNode {
+	newSlots := make([]hamtSlot, len(node.slots))
+	copy(newSlots, node.slots)
+	newSlots[position] = slot
+	return &bitmapNode{bitmap: node.bitmap, slots: newSlots}
+}
+
+func (node *bitmapNode) set(key interface{}, value interface{}, hash uint32, shift uint) (hamtNode, bool) {
+	bit := uint32(1) << fragment(hash, shift)
+	position := node.position(bit)
+	if node.bitmap&bit == 0 {
+		newSlots := make([]hamtSlot, len(node.slots)+1)
+		copy(newSlots, node.slots[:position])
+		newSlots[position] = hamtSlot{hash: hash, key: key, value: value}
+		copy(newSlots[position+1:], node.slots[position:])
+		return &bitmapNode{bitmap: node.bitmap | bit, slots: newSlots}, true
+	}
+	slot := node.slots[position]
+	if slot.node != nil {
+		newChild, added := slot.node.set(key, value, hash, shift+hamtBits)
+		return node.withSlot(position, hamtSlot{node: newChild}), added
+	}
+	if slot.hash == hash && Equal(slot.key, key) {
+		return node.withSlot(position, hamtSlot{hash: hash, key: key, value: value}), false
+	}
+	child := mergeEntries(shift+hamtBits, slot, hamtSlot{hash: hash, key: key, value: value})
+	return node.withSlot(position, hamtSlot{node: child}), true
+}
+
+// Builds the smallest subtrie holding two entries with different keys
+func mergeEntries(shift uint, first hamtSlot, second hamtSlot) hamtNode {
+	if first.hash == second.hash || shift >= hamtHashBits {
+		return &collisionNode{
+			hash: first.hash,
+			entries: []MapEntry{
+				{Key: first.key, Value: first.value},
+				{Key: second.key, Value: second.value},
+			},
+		}
+	}
+	firstFragment := fragment(first.hash, shift)
+	secondFragment := fragment(second.hash, shift)
+	if firstFragment == secondFragment {
+		return &bitmapNode{
+			bitmap: uint32(1) << firstFragment,
+			slots:  []hamtSlot{{node: mergeEntries(shift+hamtBits, first, second)}},
+		}
+	}
+	slots := []hamtSlot{first, second}
+	if secondFragment < firstFragment {
+		slots = []hamtSlot{second, first}
+	}
+	return &bitmapNode{
+		bitmap: uint32(1)<<firstFragment | uint32(1)<<secondFragment,
+		slots:  slots,
+	}
+}
+
+// Builds the smallest subtrie holding a collision node and an entry
+// whose hash differs from the hashes in the collision node
+func splitCollision(shift uint, collision *collisionNode, entry hamtSlot) hamtNode {
+	collisionFragment := fragment(collision.hash, shift)
+	entryFragment := fragment(entry.hash, shift)
+	if collisionFragment == entryFragment {
+		return &bitmapNode{
+			bitmap: uint32(1) << entryFragment,
+			slots:  []hamtSlot{{node: splitCollision(shift+hamtBits, collision, entry)}},
+		}
+	}
+	slots := []hamtSlot{{node: collision}, entry}
+	if entryFragment < collisionFragment {
+		slots = []hamtSlot{entry, {node: collision}}
+	}
+	return &bitmapNode{
+		bitmap: uint32(1)<<collisionFragment | uint32(1)<<entryFragment,
+		slots:  slots,
+	}
+}
+
+func (node *bitmapNode) remove(key interface{}, hash uint32, shift uint) (hamtNode, bool) {
+	bit := uint32(1) << fragment(hash, shift)
+	if node.bitmap&bit == 0 {
+		return node, false
+	}
+	position := node.position(bit)
+	slot := node.slots[position]
+	if slot.node != nil {
+		newChild, removed := slot.node.remove(key, hash, shift+hamtBits)
+		if !removed {
+			return node, false
+		}
+		if newChild == nil {
+			return node.withoutSlot(position, bit), true
+		}
+		// Pull single entries back up into this node, so the
+		// trie is no deeper than it needs to be
+		if entry, ok := singleEntry(newChild); ok {
+			return node.withSlot(position, entry), true
+		}
+		return node.withSlot(position, hamtSlot{node: newChild}), true
+	}
+	if slot.hash == hash && Equal(slot.key, key) {
+		return node.withoutSlot(position, bit), true
+	}
+	return node, false
+}
+
+func (node *bitmapNode) withoutSlot(position int, bit uint32) hamtNode {
+	if len(node.slots) == 1 {
+		return nil
+	}
+	newSlots := make([]hamtSlot, len(node.slots)-1)
+	copy(newSlots, node.slots[:position])
+	copy(newSlots[position:], node.slots[position+1:])
+	return &bitmapNode{bitmap: node.bitmap &^ bit, slots: newSlots}
+}
+
+// Returns the only entry of a node that holds exactly one entry
+func singleEntry(node hamtNode) (hamtSlot, bool) {
+	switch n := node.(type) {
+	case *bitmapNode:
+		if len(n.slots) == 1 && n.slots[0].node == nil {
+			return n.slots[0], true
+		}
+	case *collisionNode:
+		if len(n.entries) == 1 {
+			return hamtSlot{hash: n.hash, key: n.entries[0].Key, value: n.entries[0].Value}, true
+		}
+	}
+	return hamtSlot{}, false
+}
+
+func (node *collisionNode) indexOf(key interface{}) int {
+	for i, entry := range node.entries {
+		if Equal(entry.Key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (node *collisionNode) get(key interface{}, hash uint32, shift uint) (interface{}, bool) {
+	if hash != node.hash {
+		return nil, false
+	}
+	index := node.indexOf(key)
+	if index < 0 {
+		return nil, false
+	}
+	return node.entries[index].Value, true
+}
+
+func (node *collisionNode) set(key interface{}, value interface{}, hash uint32, shift uint) (hamtNode, bool) {
+	if hash != node.hash {
+		return splitCollision(shift, node, hamtSlot{hash: hash, key: key, value: value}), true
+	}
+	index := node.indexOf(key)
+	if index < 0 {
+		newEntries := make([]MapEntry, len(node.entries), len(node.entries)+1)
+		copy(newEntries, node.entries)
+		newEntries = append(newEntries, MapEntry{Key: key, Value: value})
+		return &collisionNode{hash: node.hash, entries: newEntries}, true
+	}
+	newEntries := make([]MapEntry, len(node.entries))
+	copy(newEntries, node.entries)
+	newEntries[index] = MapEntry{Key: key, Value: value}
+	return &collisionNode{hash: node.hash, entries: newEntries}, false
+}
+
+func (node *collisionNode) remove(key interface{}, hash uint32, shift uint) (hamtNode, bool) {
+	index := node.indexOf(key)
+	if hash != node.hash || index < 0 {
+		return node, false
+	}
+	if len(node.entries) == 1 {
+		return nil, true
+	}
+	newEntries := make([]MapEntry, 0, len(node.entries)-1)
+	newEntries = append(newEntries, node.entries[:index]...)
+	newEntries = append(newEntries, node.entries[index+1:]...)
+	return &collisionNode{hash: node.hash, entries: newEntries}, true
+}
+
+type HashMap struct {
+	root *bitmapNode
+	size int
+}
+
+func EmptyHashMap() *HashMap {
+	return &HashMap{root: emptyBitmapNode}
+}
+
+// Builds a map from alternating keys and values
+func NewHashMap(keysAndValues ...interface{}) *HashMap {
+	hashMap := EmptyHashMap()
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		hashMap = hashMap.Set(keysAndValues[i], keysAndValues[i+1]).(*HashMap)
+	}
+	return hashMap
+}
+
+func newHashMapFromRoot(root hamtNode, size int) *HashMap {
+	if root == nil {
+		return EmptyHashMap()
+	}
+	return &HashMap{root: root.(*bitmapNode), size: size}
+}
+
+func (hashMap *HashMap) Size() int {
+	return hashMap.size
+}
+
+func (hashMap *HashMap) Get(key interface{}) (interface{}, bool) {
+	return hashMap.root.get(key, Hash(key), 0)
+}
+
+func (hashMap *HashMap) Contains(key interface{}) bool {
+	_, found := hashMap.Get(key)
+	return found
+}
+
+func (hashMap *HashMap) Set(key interface{}, value interface{}) Map {
+	newRoot, added := hashMap.root.set(key, value, Hash(key), 0)
+	size := hashMap.size
+	if added {
+		size++
+	}
+	return newHashMapFromRoot(newRoot, size)
+}
+
+func (hashMap *HashMap) Remove(key interface{}) Map {
+	newRoot, removed := hashMap.root.remove(key, Hash(key), 0)
+	if !removed {
+		return hashMap
+	}
+	return newHashMapFromRoot(newRoot, hashMap.size-1)
+}
+
+// Returns a map with the entries of both maps. Where both maps
+// have a key, the value from other is used
+func (hashMap *HashMap) Merge(other Map) Map {
+	var merged Map = hashMap
+	iterator := other.Iterator()
+	for iterator.MoveNext() {
+		entry := iterator.Current().(MapEntry)
+		merged = merged.Set(entry.Key, entry.Value)
+	}
+	return merged
+}
+
+func (hashMap *HashMap) Keys() Iterable {
+	return hashMap.Map(func(entry interface{}) interface{} {
+		return entry.(MapEntry).Key
+	})
+}
+
+func (hashMap *HashMap) Values() Iterable {
+	return hashMap.Map(func(entry interface{}) interface{} {
+		return entry.(MapEntry).Value
+	})
+}
+
+func (hashMap *HashMap) KeySet() Set {
+	return &HashSet{entries: hashMap}
+}
+
+func (hashMap *HashMap) Iterator() Iterator {
+	return &hamtIterator{stack: []hamtIteratorFrame{{node: hashMap.root, index: -1}}}
+}
+
+func (hashMap *HashMap) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(hashMap, mapFn)
+}
+
+func (hashMap *HashMap) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(hashMap, filterFn)
+}
+
+func (hashMap *HashMap) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(hashMap, initialValue, reducerFn)
+}
+
+func (hashMap *HashMap) ForEach(iterFn func(interface{})) {
+	forEachHelper(hashMap, iterFn)
+}
+
+func (hashMap *HashMap) ToSlice() []interface{} {
+	return toSliceHelper(hashMap)
+}
+
+type hamtIteratorFrame struct {
+	node  hamtNode
+	index int
+}
+
+// Walks the trie depth first, producing MapEntries
+type hamtIterator struct {
+	stack   []hamtIteratorFrame
+	current MapEntry
+	started bool
+	done    bool
+}
+
+func (iterator *hamtIterator) MoveNext() bool {
+	iterator.started = true
+	for len(iterator.stack) > 0 {
+		top := &iterator.stack[len(iterator.stack)-1]
+		top.index++
+		switch node := top.node.(type) {
+		case *bitmapNode:
+			if top.index >= len(node.slots) {
+				iterator.stack = iterator.stack[:len(iterator.stack)-1]
+				continue
+			}
+			slot := node.slots[top.index]
+			if slot.node != nil {
+				iterator.stack = append(iterator.stack, hamtIteratorFrame{node: slot.node, index: -1})
+				continue
+			}
+			iterator.current = MapEntry{Key: slot.key, Value: slot.value}
+			return true
+		case *collisionNode:
+			if top.index >= len(node.entries) {
+				iterator.stack = iterator.stack[:len(iterator.stack)-1]
+				continue
+			}
+			iterator.current = node.entries[top.index]
+			return true
+		}
+	}
+	iterator.done = true
+	return false
+}
+
+func (iterator *hamtIterator) Current() interface{} {
+	if !iterator.started || iterator.done {
+		panic(ErrIterationOutOfRange)
+	}
+	return iterator.current
+}
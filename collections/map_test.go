@@ -0,0 +1,154 @@
+package collections
+
+import (
+	"sort"
+	"testing"
+)
+
+// A key whose hash collides with every other key of the same bucket
+type collidingKey struct {
+	bucket uint32
+	id     int
+}
+
+func (key collidingKey) Hash() uint32 {
+	return key.bucket
+}
+
+func (key collidingKey) Equals(other interface{}) bool {
+	otherKey, ok := other.(collidingKey)
+	return ok && otherKey == key
+}
+
+func buildHashMap(size int) Map {
+	var hashMap Map = EmptyHashMap()
+	for i := 0; i < size; i++ {
+		hashMap = hashMap.Set(i, i*10)
+	}
+	return hashMap
+}
+
+func TestHashMapSetAndGet(t *testing.T) {
+	hashMap := buildHashMap(5000)
+	if hashMap.(*HashMap).Size() != 5000 {
+		t.Fatalf("expected size 5000, got %v", hashMap.(*HashMap).Size())
+	}
+	for i := 0; i < 5000; i++ {
+		value, found := hashMap.Get(i)
+		if !found || value != i*10 {
+			t.Fatalf("expected %v for key %v, got %v (found %v)", i*10, i, value, found)
+		}
+	}
+	if _, found := hashMap.Get(5000); found {
+		t.Fatalf("found a key that was never added")
+	}
+}
+
+func TestHashMapIsPersistent(t *testing.T) {
+	original := buildHashMap(100)
+	updated := original.Set(1, "updated").Remove(2).Set(100, 1000)
+
+	if value, _ := original.Get(1); value != 10 {
+		t.Fatalf("original map was mutated by Set")
+	}
+	if !original.Contains(2) {
+		t.Fatalf("original map was mutated by Remove")
+	}
+	if original.Contains(100) {
+		t.Fatalf("original map was mutated by adding a key")
+	}
+	if value, _ := updated.Get(1); value != "updated" {
+		t.Fatalf("expected updated value, got %v", value)
+	}
+	if updated.Contains(2) {
+		t.Fatalf("expected key to be removed")
+	}
+	if updated.(*HashMap).Size() != 100 {
+		t.Fatalf("expected size 100, got %v", updated.(*HashMap).Size())
+	}
+}
+
+func TestHashMapRemove(t *testing.T) {
+	hashMap := buildHashMap(2000)
+	for i := 0; i < 2000; i += 2 {
+		hashMap = hashMap.Remove(i)
+	}
+	for i := 0; i < 2000; i++ {
+		if hashMap.Contains(i) != (i%2 == 1) {
+			t.Fatalf("unexpected membership of %v after removal", i)
+		}
+	}
+	for i := 1; i < 2000; i += 2 {
+		hashMap = hashMap.Remove(i)
+	}
+	if hashMap.(*HashMap).Size() != 0 {
+		t.Fatalf("expected empty map, got size %v", hashMap.(*HashMap).Size())
+	}
+	if hashMap.Iterator().MoveNext() {
+		t.Fatalf("expected empty map to have nothing to iterate")
+	}
+}
+
+func TestHashMapCollisions(t *testing.T) {
+	var hashMap Map = EmptyHashMap()
+	for i := 0; i < 10; i++ {
+		hashMap = hashMap.Set(collidingKey{bucket: 7, id: i}, i)
+	}
+	// A key with a different hash sharing the collision's
+	// position at the first few levels of the trie
+	hashMap = hashMap.Set(collidingKey{bucket: 7 + 1<<20, id: 0}, "other")
+	for i := 0; i < 10; i++ {
+		value, found := hashMap.Get(collidingKey{bucket: 7, id: i})
+		if !found || value != i {
+			t.Fatalf("expected %v for colliding key %v, got %v", i, i, value)
+		}
+	}
+	if value, _ := hashMap.Get(collidingKey{bucket: 7 + 1<<20, id: 0}); value != "other" {
+		t.Fatalf("expected other, got %v", value)
+	}
+	for i := 0; i < 10; i++ {
+		hashMap = hashMap.Remove(collidingKey{bucket: 7, id: i})
+	}
+	if hashMap.(*HashMap).Size() != 1 || !hashMap.Contains(collidingKey{bucket: 7 + 1<<20, id: 0}) {
+		t.Fatalf("unexpected map after removing colliding keys")
+	}
+}
+
+func TestHashMapIteration(t *testing.T) {
+	hashMap := buildHashMap(1000)
+	keys := []int{}
+	hashMap.ForEach(func(entry interface{}) {
+		mapEntry := entry.(MapEntry)
+		if mapEntry.Value != mapEntry.Key.(int)*10 {
+			t.Fatalf("unexpected entry %v", mapEntry)
+		}
+		keys = append(keys, mapEntry.Key.(int))
+	})
+	sort.Ints(keys)
+	if len(keys) != 1000 {
+		t.Fatalf("expected 1000 keys, got %v", len(keys))
+	}
+	for i, key := range keys {
+		if key != i {
+			t.Fatalf("expected key %v, got %v", i, key)
+		}
+	}
+}
+
+func TestHashMapMerge(t *testing.T) {
+	left := NewHashMap("a", 1, "b", 2)
+	right := NewHashMap("b", 3, "c", 4)
+	merged := left.Merge(right)
+	for key, expected := range map[string]int{"a": 1, "b": 3, "c": 4} {
+		if value, _ := merged.Get(key); value != expected {
+			t.Fatalf("expected %v for %v, got %v", expected, key, value)
+		}
+	}
+	if !merged.KeySet().Contains("c") {
+		t.Fatalf("expected key set to contain merged key")
+	}
+}
+
+func TestHashUnhashable(t *testing.T) {
+	shouldPanic(t, func() { EmptyHashMap().Set([]int{1}, 1) }, ErrUnhashable)
+}
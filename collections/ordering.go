@@ -0,0 +1,46 @@
+package collections
+
+// A Comparable is a value that defines its own ordering, and so can
+// be stored in sorted collections like SortedMap and SortedSet
+type Comparable interface {
+	// Returns a negative number, zero or a positive number if this
+	// value is less than, equal to or greater than other
+	Compare(other interface{}) int
+}
+
+// Compares two values stored in a sorted collection. Sorted collections
+// support Comparable values, and strings, ints and floats compared with
+// values of the same type. Any other comparison panics with ErrIncomparable
+func Compare(left interface{}, right interface{}) int {
+	switch l := left.(type) {
+	case Comparable:
+		return l.Compare(right)
+	case string:
+		if r, ok := right.(string); ok {
+			return compareOrdered(l < r, l > r)
+		}
+	case int:
+		if r, ok := right.(int); ok {
+			return compareOrdered(l < r, l > r)
+		}
+	case int64:
+		if r, ok := right.(int64); ok {
+			return compareOrdered(l < r, l > r)
+		}
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareOrdered(l < r, l > r)
+		}
+	}
+	panic(ErrIncomparable)
+}
+
+func compareOrdered(less bool, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
@@ -1 +1,115 @@
 package collections
+
+// Range is a lazy sequence of evenly spaced integers. Elements are
+// computed on demand, so a range of any size takes constant space
+// and you can expect:
+// Size, random access and membership tests (O(1))
+// Iteration (O(n))
+//
+// Ranges count from start towards stop in increments of step, which
+// may be negative. Ranges built with NewRange exclude stop, ranges
+// built with NewInclusiveRange include it if it is reached.
+
+type Range struct {
+	start int
+	step  int
+	size  int
+}
+
+func newRange(start int, stop int, step int, inclusive bool) *Range {
+	if step == 0 {
+		panic(ErrZeroStep)
+	}
+	// Work with the distance and step as if counting upwards
+	distance, stride := stop-start, step
+	if step < 0 {
+		distance, stride = -distance, -step
+	}
+	size := 0
+	if distance > 0 || (inclusive && distance == 0) {
+		size = distance/stride + 1
+		if !inclusive && distance%stride == 0 {
+			size--
+		}
+	}
+	return &Range{start: start, step: step, size: size}
+}
+
+func NewRange(start int, stop int, step int) *Range {
+	return newRange(start, stop, step, false)
+}
+
+func NewInclusiveRange(start int, stop int, step int) *Range {
+	return newRange(start, stop, step, true)
+}
+
+func (r *Range) Start() int {
+	return r.start
+}
+
+func (r *Range) Step() int {
+	return r.step
+}
+
+func (r *Range) Size() int {
+	return r.size
+}
+
+func (r *Range) Get(index int) interface{} {
+	if index < 0 || index >= r.size {
+		panic(ErrIndexOutOfRange)
+	}
+	return r.start + index*r.step
+}
+
+func (r *Range) Contains(value int) bool {
+	offset := value - r.start
+	if offset%r.step != 0 {
+		return false
+	}
+	index := offset / r.step
+	return index >= 0 && index < r.size
+}
+
+type rangeIterator struct {
+	r     *Range
+	index int
+}
+
+func (iterator *rangeIterator) MoveNext() bool {
+	if iterator.index < iterator.r.size {
+		iterator.index++
+	}
+	return iterator.index < iterator.r.size
+}
+
+func (iterator *rangeIterator) Current() interface{} {
+	if iterator.index < 0 || iterator.index >= iterator.r.size {
+		panic(ErrIterationOutOfRange)
+	}
+	return iterator.r.start + iterator.index*iterator.r.step
+}
+
+func (r *Range) Iterator() Iterator {
+	return &rangeIterator{r: r, index: -1}
+}
+
+func (r *Range) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(r, mapFn)
+}
+
+func (r *Range) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(r, filterFn)
+}
+
+func (r *Range) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(r, initialValue, reducerFn)
+}
+
+func (r *Range) ForEach(iterFn func(interface{})) {
+	forEachHelper(r, iterFn)
+}
+
+func (r *Range) ToSlice() []interface{} {
+	return toSliceHelper(r)
+}
@@ -0,0 +1,70 @@
+package collections
+
+import "testing"
+
+func checkRange(t *testing.T, r *Range, expected ...int) {
+	t.Helper()
+	values := r.ToSlice()
+	if r.Size() != len(expected) || len(values) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+	for i, value := range expected {
+		if values[i] != value || r.Get(i) != value {
+			t.Fatalf("expected %v, got %v", expected, values)
+		}
+	}
+}
+
+func TestRangeBounds(t *testing.T) {
+	checkRange(t, NewRange(0, 5, 1), 0, 1, 2, 3, 4)
+	checkRange(t, NewRange(0, 10, 3), 0, 3, 6, 9)
+	checkRange(t, NewRange(0, 9, 3), 0, 3, 6)
+	checkRange(t, NewRange(5, 0, 1))
+	checkRange(t, NewRange(3, 3, 1))
+}
+
+func TestNegativeStepRange(t *testing.T) {
+	checkRange(t, NewRange(5, 0, -1), 5, 4, 3, 2, 1)
+	checkRange(t, NewRange(10, 0, -4), 10, 6, 2)
+	checkRange(t, NewRange(0, 5, -1))
+}
+
+func TestInclusiveRange(t *testing.T) {
+	checkRange(t, NewInclusiveRange(1, 5, 1), 1, 2, 3, 4, 5)
+	checkRange(t, NewInclusiveRange(0, 9, 3), 0, 3, 6, 9)
+	checkRange(t, NewInclusiveRange(0, 10, 3), 0, 3, 6, 9)
+	checkRange(t, NewInclusiveRange(3, 3, 1), 3)
+	checkRange(t, NewInclusiveRange(5, 1, -2), 5, 3, 1)
+}
+
+func TestRangeZeroStep(t *testing.T) {
+	shouldPanic(t, func() { NewRange(0, 5, 0) }, ErrZeroStep)
+}
+
+func TestRangeContains(t *testing.T) {
+	r := NewRange(10, 0, -2)
+	for _, value := range []int{10, 8, 2} {
+		if !r.Contains(value) {
+			t.Fatalf("expected range to contain %v", value)
+		}
+	}
+	for _, value := range []int{0, 12, 3, -2} {
+		if r.Contains(value) {
+			t.Fatalf("expected range not to contain %v", value)
+		}
+	}
+	shouldPanic(t, func() { r.Get(5) }, ErrIndexOutOfRange)
+}
+
+func TestLargeRangeIsLazy(t *testing.T) {
+	r := NewRange(0, 1<<40, 1)
+	if r.Size() != 1<<40 || r.Get(1<<39) != 1<<39 {
+		t.Fatalf("unexpected large range")
+	}
+	iterator := r.Iterator()
+	iterator.MoveNext()
+	iterator.MoveNext()
+	if iterator.Current() != 1 {
+		t.Fatalf("expected 1, got %v", iterator.Current())
+	}
+}
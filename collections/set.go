@@ -0,0 +1,106 @@
+package collections
+
+// HashSet is a persistent set backed by a HashMap whose
+// keys are the elements of the set
+
+type HashSet struct {
+	entries *HashMap
+}
+
+func EmptyHashSet() *HashSet {
+	return &HashSet{entries: EmptyHashMap()}
+}
+
+func NewHashSet(values ...interface{}) *HashSet {
+	var set Set = EmptyHashSet()
+	for _, value := range values {
+		set = set.Add(value)
+	}
+	return set.(*HashSet)
+}
+
+func (set *HashSet) Size() int {
+	return set.entries.Size()
+}
+
+func (set *HashSet) Contains(value interface{}) bool {
+	return set.entries.Contains(value)
+}
+
+func (set *HashSet) Add(value interface{}) Set {
+	if set.Contains(value) {
+		return set
+	}
+	return &HashSet{entries: set.entries.Set(value, struct{}{}).(*HashMap)}
+}
+
+func (set *HashSet) Remove(value interface{}) Set {
+	return &HashSet{entries: set.entries.Remove(value).(*HashMap)}
+}
+
+func (set *HashSet) SubsetOf(other Set) bool {
+	if set.Size() > other.Size() {
+		return false
+	}
+	iterator := set.Iterator()
+	for iterator.MoveNext() {
+		if !other.Contains(iterator.Current()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *HashSet) Union(other Set) Set {
+	var union Set = set
+	iterator := other.Iterator()
+	for iterator.MoveNext() {
+		union = union.Add(iterator.Current())
+	}
+	return union
+}
+
+func (set *HashSet) Intersect(other Set) Set {
+	var intersection Set = EmptyHashSet()
+	iterator := set.Iterator()
+	for iterator.MoveNext() {
+		value := iterator.Current()
+		if other.Contains(value) {
+			intersection = intersection.Add(value)
+		}
+	}
+	return intersection
+}
+
+func (set *HashSet) Difference(other Set) Set {
+	var difference Set = set
+	iterator := other.Iterator()
+	for iterator.MoveNext() {
+		difference = difference.Remove(iterator.Current())
+	}
+	return difference
+}
+
+func (set *HashSet) Iterator() Iterator {
+	return set.entries.Keys().Iterator()
+}
+
+func (set *HashSet) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(set, mapFn)
+}
+
+func (set *HashSet) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(set, filterFn)
+}
+
+func (set *HashSet) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(set, initialValue, reducerFn)
+}
+
+func (set *HashSet) ForEach(iterFn func(interface{})) {
+	forEachHelper(set, iterFn)
+}
+
+func (set *HashSet) ToSlice() []interface{} {
+	return toSliceHelper(set)
+}
@@ -0,0 +1,39 @@
+package collections
+
+import "testing"
+
+func TestHashSetOperations(t *testing.T) {
+	evens := NewHashSet(0, 2, 4, 6)
+	small := NewHashSet(0, 1, 2, 3)
+
+	union := evens.Union(small)
+	if union.Size() != 6 {
+		t.Fatalf("expected union of size 6, got %v", union.Size())
+	}
+	intersection := evens.Intersect(small)
+	if intersection.Size() != 2 || !intersection.Contains(0) || !intersection.Contains(2) {
+		t.Fatalf("unexpected intersection %v", intersection.ToSlice())
+	}
+	difference := evens.Difference(small)
+	if difference.Size() != 2 || !difference.Contains(4) || !difference.Contains(6) {
+		t.Fatalf("unexpected difference %v", difference.ToSlice())
+	}
+	if !intersection.SubsetOf(evens) || evens.SubsetOf(intersection) {
+		t.Fatalf("unexpected subset relationship")
+	}
+}
+
+func TestHashSetIsPersistent(t *testing.T) {
+	original := NewHashSet("a", "b")
+	added := original.Add("c")
+	removed := original.Remove("a")
+	if original.Size() != 2 || original.Contains("c") || !original.Contains("a") {
+		t.Fatalf("original set was mutated")
+	}
+	if added.Size() != 3 || removed.Size() != 1 {
+		t.Fatalf("unexpected sizes %v and %v", added.Size(), removed.Size())
+	}
+	if original.Add("a") != original {
+		t.Fatalf("adding an existing element should return the same set")
+	}
+}
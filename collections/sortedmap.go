@@ -1 +1,399 @@
 package collections
+
+// SortedMap is a persistent map backed by a red-black tree that keeps
+// its entries ordered by key. Insertion follows Chris Okasaki's
+// "Red-Black Trees in a Functional Setting" and deletion follows
+// Stefan Kahrs' "Red-Black Trees with Types". You can expect:
+// Lookup, insertion and removal (O(log n))
+// First and Last (O(log n))
+// Iteration in key order (O(n))
+//
+// Updates copy only the path from the root to the changed entry.
+//
+// Keys are ordered with Compare, so every key in a map must be
+// comparable with every other
+
+type color bool
+
+const (
+	red   color = true
+	black color = false
+)
+
+type rbNode struct {
+	color color
+	left  *rbNode
+	key   interface{}
+	value interface{}
+	right *rbNode
+}
+
+func newRBNode(c color, left *rbNode, key interface{}, value interface{}, right *rbNode) *rbNode {
+	return &rbNode{color: c, left: left, key: key, value: value, right: right}
+}
+
+func (node *rbNode) isRed() bool {
+	return node != nil && node.color == red
+}
+
+func (node *rbNode) isBlack() bool {
+	return node != nil && node.color == black
+}
+
+func (node *rbNode) withColor(c color) *rbNode {
+	if node == nil || node.color == c {
+		return node
+	}
+	return newRBNode(c, node.left, node.key, node.value, node.right)
+}
+
+func (node *rbNode) get(key interface{}) (*rbNode, bool) {
+	for node != nil {
+		comparison := Compare(key, node.key)
+		switch {
+		case comparison < 0:
+			node = node.left
+		case comparison > 0:
+			node = node.right
+		default:
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// Rebuilds a black node whose children may include a red node with
+// a red child, so that neither child is red-red
+func balance(left *rbNode, key interface{}, value interface{}, right *rbNode) *rbNode {
+	switch {
+	case left.isRed() && right.isRed():
+		return newRBNode(red, left.withColor(black), key, value, right.withColor(black))
+	case left.isRed() && left.left.isRed():
+		return newRBNode(red,
+			left.left.withColor(black),
+			left.key, left.value,
+			newRBNode(black, left.right, key, value, right))
+	case left.isRed() && left.right.isRed():
+		return newRBNode(red,
+			newRBNode(black, left.left, left.key, left.value, left.right.left),
+			left.right.key, left.right.value,
+			newRBNode(black, left.right.right, key, value, right))
+	case right.isRed() && right.right.isRed():
+		return newRBNode(red,
+			newRBNode(black, left, key, value, right.left),
+			right.key, right.value,
+			right.right.withColor(black))
+	case right.isRed() && right.left.isRed():
+		return newRBNode(red,
+			newRBNode(black, left, key, value, right.left.left),
+			right.left.key, right.left.value,
+			newRBNode(black, right.left.right, right.key, right.value, right.right))
+	}
+	return newRBNode(black, left, key, value, right)
+}
+
+// Returns the updated subtree and whether a new key was added
+func (node *rbNode) insert(key interface{}, value interface{}) (*rbNode, bool) {
+	if node == nil {
+		return newRBNode(red, nil, key, value, nil), true
+	}
+	comparison := Compare(key, node.key)
+	switch {
+	case comparison < 0:
+		left, added := node.left.insert(key, value)
+		if node.color == black {
+			return balance(left, node.key, node.value, node.right), added
+		}
+		return newRBNode(red, left, node.key, node.value, node.right), added
+	case comparison > 0:
+		right, added := node.right.insert(key, value)
+		if node.color == black {
+			return balance(node.left, node.key, node.value, right), added
+		}
+		return newRBNode(red, node.left, node.key, node.value, right), added
+	}
+	return newRBNode(node.color, node.left, key, value, node.right), false
+}
+
+// Restores balance after the black height of the left subtree
+// has shrunk by one
+func balanceLeft(left *rbNode, key interface{}, value interface{}, right *rbNode) *rbNode {
+	switch {
+	case left.isRed():
+		return newRBNode(red, left.withColor(black), key, value, right)
+	case right.isBlack():
+		return balance(left, key, value, right.withColor(red))
+	case right.isRed() && right.left.isBlack():
+		return newRBNode(red,
+			newRBNode(black, left, key, value, right.left.left),
+			right.left.key, right.left.value,
+			balance(right.left.right, right.key, right.value, right.right.withColor(red)))
+	}
+	panic("red-black tree invariant violated")
+}
+
+// Restores balance after the black height of the right subtree
+// has shrunk by one
+func balanceRight(left *rbNode, key interface{}, value interface{}, right *rbNode) *rbNode {
+	switch {
+	case right.isRed():
+		return newRBNode(red, left, key, value, right.withColor(black))
+	case left.isBlack():
+		return balance(left.withColor(red), key, value, right)
+	case left.isRed() && left.right.isBlack():
+		return newRBNode(red,
+			balance(left.left.withColor(red), left.key, left.value, left.right.left),
+			left.right.key, left.right.value,
+			newRBNode(black, left.right.right, key, value, right))
+	}
+	panic("red-black tree invariant violated")
+}
+
+// Joins two subtrees of the same black height, where every key in
+// left is less than every key in right
+func join(left *rbNode, right *rbNode) *rbNode {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.isRed() && right.isRed():
+		middle := join(left.right, right.left)
+		if middle.isRed() {
+			return newRBNode(red,
+				newRBNode(red, left.left, left.key, left.value, middle.left),
+				middle.key, middle.value,
+				newRBNode(red, middle.right, right.key, right.value, right.right))
+		}
+		return newRBNode(red, left.left, left.key, left.value,
+			newRBNode(red, middle, right.key, right.value, right.right))
+	case left.isBlack() && right.isBlack():
+		middle := join(left.right, right.left)
+		if middle.isRed() {
+			return newRBNode(red,
+				newRBNode(black, left.left, left.key, left.value, middle.left),
+				middle.key, middle.value,
+				newRBNode(black, middle.right, right.key, right.value, right.right))
+		}
+		return balanceLeft(left.left, left.key, left.value,
+			newRBNode(black, middle, right.key, right.value, right.right))
+	case right.isRed():
+		return newRBNode(red, join(left, right.left), right.key, right.value, right.right)
+	}
+	return newRBNode(red, left.left, left.key, left.value, join(left.right, right))
+}
+
+// Returns the updated subtree and whether the key was found. The
+// result may have a red root
+func (node *rbNode) delete(key interface{}) (*rbNode, bool) {
+	if node == nil {
+		return nil, false
+	}
+	comparison := Compare(key, node.key)
+	switch {
+	case comparison < 0:
+		left, removed := node.left.delete(key)
+		if !removed {
+			return node, false
+		}
+		if node.left.isBlack() {
+			return balanceLeft(left, node.key, node.value, node.right), true
+		}
+		return newRBNode(red, left, node.key, node.value, node.right), true
+	case comparison > 0:
+		right, removed := node.right.delete(key)
+		if !removed {
+			return node, false
+		}
+		if node.right.isBlack() {
+			return balanceRight(node.left, node.key, node.value, right), true
+		}
+		return newRBNode(red, node.left, node.key, node.value, right), true
+	}
+	return join(node.left, node.right), true
+}
+
+type SortedMap struct {
+	root *rbNode
+	size int
+}
+
+func EmptySortedMap() *SortedMap {
+	return &SortedMap{}
+}
+
+// Builds a map from alternating keys and values
+func NewSortedMap(keysAndValues ...interface{}) *SortedMap {
+	sortedMap := EmptySortedMap()
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		sortedMap = sortedMap.Set(keysAndValues[i], keysAndValues[i+1]).(*SortedMap)
+	}
+	return sortedMap
+}
+
+func (sortedMap *SortedMap) Size() int {
+	return sortedMap.size
+}
+
+func (sortedMap *SortedMap) Get(key interface{}) (interface{}, bool) {
+	node, found := sortedMap.root.get(key)
+	if !found {
+		return nil, false
+	}
+	return node.value, true
+}
+
+func (sortedMap *SortedMap) Contains(key interface{}) bool {
+	_, found := sortedMap.root.get(key)
+	return found
+}
+
+func (sortedMap *SortedMap) Set(key interface{}, value interface{}) Map {
+	root, added := sortedMap.root.insert(key, value)
+	size := sortedMap.size
+	if added {
+		size++
+	}
+	return &SortedMap{root: root.withColor(black), size: size}
+}
+
+func (sortedMap *SortedMap) Remove(key interface{}) Map {
+	root, removed := sortedMap.root.delete(key)
+	if !removed {
+		return sortedMap
+	}
+	return &SortedMap{root: root.withColor(black), size: sortedMap.size - 1}
+}
+
+// Returns a map with the entries of both maps. Where both maps
+// have a key, the value from other is used
+func (sortedMap *SortedMap) Merge(other Map) Map {
+	var merged Map = sortedMap
+	iterator := other.Iterator()
+	for iterator.MoveNext() {
+		entry := iterator.Current().(MapEntry)
+		merged = merged.Set(entry.Key, entry.Value)
+	}
+	return merged
+}
+
+// Returns the entry with the smallest key
+func (sortedMap *SortedMap) First() MapEntry {
+	node := sortedMap.root
+	if node == nil {
+		panic(ErrEmptyCollection)
+	}
+	for node.left != nil {
+		node = node.left
+	}
+	return MapEntry{Key: node.key, Value: node.value}
+}
+
+// Returns the entry with the largest key
+func (sortedMap *SortedMap) Last() MapEntry {
+	node := sortedMap.root
+	if node == nil {
+		panic(ErrEmptyCollection)
+	}
+	for node.right != nil {
+		node = node.right
+	}
+	return MapEntry{Key: node.key, Value: node.value}
+}
+
+// Returns the entries with keys from from up to, but not including,
+// to, in key order
+func (sortedMap *SortedMap) Between(from interface{}, to interface{}) Iterable {
+	return NewStream(newRBIterator(sortedMap.root, from, to, true))
+}
+
+func (sortedMap *SortedMap) Keys() Iterable {
+	return sortedMap.Map(func(entry interface{}) interface{} {
+		return entry.(MapEntry).Key
+	})
+}
+
+func (sortedMap *SortedMap) Values() Iterable {
+	return sortedMap.Map(func(entry interface{}) interface{} {
+		return entry.(MapEntry).Value
+	})
+}
+
+func (sortedMap *SortedMap) KeySet() Set {
+	return &SortedSet{entries: sortedMap}
+}
+
+func (sortedMap *SortedMap) Iterator() Iterator {
+	return newRBIterator(sortedMap.root, nil, nil, false)
+}
+
+func (sortedMap *SortedMap) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(sortedMap, mapFn)
+}
+
+func (sortedMap *SortedMap) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(sortedMap, filterFn)
+}
+
+func (sortedMap *SortedMap) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(sortedMap, initialValue, reducerFn)
+}
+
+func (sortedMap *SortedMap) ForEach(iterFn func(interface{})) {
+	forEachHelper(sortedMap, iterFn)
+}
+
+func (sortedMap *SortedMap) ToSlice() []interface{} {
+	return toSliceHelper(sortedMap)
+}
+
+// Walks the tree in order, producing MapEntries. A bounded iterator
+// skips subtrees below from and stops at the first key not below to
+type rbIterator struct {
+	stack   []*rbNode
+	to      interface{}
+	bounded bool
+	current MapEntry
+	started bool
+	done    bool
+}
+
+func newRBIterator(root *rbNode, from interface{}, to interface{}, bounded bool) *rbIterator {
+	iterator := &rbIterator{to: to, bounded: bounded}
+	node := root
+	for node != nil {
+		if bounded && Compare(node.key, from) < 0 {
+			node = node.right
+			continue
+		}
+		iterator.stack = append(iterator.stack, node)
+		node = node.left
+	}
+	return iterator
+}
+
+func (iterator *rbIterator) MoveNext() bool {
+	iterator.started = true
+	if iterator.done || len(iterator.stack) == 0 {
+		iterator.done = true
+		return false
+	}
+	node := iterator.stack[len(iterator.stack)-1]
+	if iterator.bounded && Compare(node.key, iterator.to) >= 0 {
+		iterator.done = true
+		return false
+	}
+	iterator.stack = iterator.stack[:len(iterator.stack)-1]
+	for child := node.right; child != nil; child = child.left {
+		iterator.stack = append(iterator.stack, child)
+	}
+	iterator.current = MapEntry{Key: node.key, Value: node.value}
+	return true
+}
+
+func (iterator *rbIterator) Current() interface{} {
+	if !iterator.started || iterator.done {
+		panic(ErrIterationOutOfRange)
+	}
+	return iterator.current
+}
@@ -0,0 +1,128 @@
+package collections
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Checks that no red node has a red child and that every path from
+// the root has the same number of black nodes, returning that number
+func checkRedBlackInvariants(t *testing.T, node *rbNode) int {
+	t.Helper()
+	if node == nil {
+		return 1
+	}
+	if node.isRed() && (node.left.isRed() || node.right.isRed()) {
+		t.Fatalf("red node %v has a red child", node.key)
+	}
+	left := checkRedBlackInvariants(t, node.left)
+	right := checkRedBlackInvariants(t, node.right)
+	if left != right {
+		t.Fatalf("unequal black heights below %v", node.key)
+	}
+	if node.color == black {
+		return left + 1
+	}
+	return left
+}
+
+func checkSortedKeys(t *testing.T, sortedMap *SortedMap, expected []int) {
+	t.Helper()
+	keys := sortedMap.Keys().ToSlice()
+	if len(keys) != len(expected) || sortedMap.Size() != len(expected) {
+		t.Fatalf("expected keys %v, got %v", expected, keys)
+	}
+	for i, key := range expected {
+		if keys[i] != key {
+			t.Fatalf("expected keys %v, got %v", expected, keys)
+		}
+	}
+}
+
+func TestSortedMapGetAndSet(t *testing.T) {
+	sortedMap := NewSortedMap("b", 2, "a", 1, "c", 3)
+	value, found := sortedMap.Get("a")
+	if !found || value != 1 {
+		t.Fatalf("expected 1, got %v", value)
+	}
+	if _, found := sortedMap.Get("d"); found {
+		t.Fatalf("should not have found d")
+	}
+	replaced := sortedMap.Set("a", 10)
+	value, _ = replaced.Get("a")
+	if value != 10 || replaced.(*SortedMap).Size() != 3 {
+		t.Fatalf("set should replace existing keys")
+	}
+	value, _ = sortedMap.Get("a")
+	if value != 1 {
+		t.Fatalf("original map was mutated")
+	}
+}
+
+func TestSortedMapOrderedIteration(t *testing.T) {
+	keys := rand.New(rand.NewSource(1)).Perm(500)
+	sortedMap := EmptySortedMap()
+	for _, key := range keys {
+		sortedMap = sortedMap.Set(key, key*2).(*SortedMap)
+		checkRedBlackInvariants(t, sortedMap.root)
+	}
+	expected := make([]int, 500)
+	for i := range expected {
+		expected[i] = i
+	}
+	checkSortedKeys(t, sortedMap, expected)
+	entry := sortedMap.ToSlice()[10].(MapEntry)
+	if entry.Key != 10 || entry.Value != 20 {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+}
+
+func TestSortedMapRemove(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	sortedMap := EmptySortedMap()
+	for _, key := range random.Perm(300) {
+		sortedMap = sortedMap.Set(key, key).(*SortedMap)
+	}
+	remaining := map[int]bool{}
+	for i := 0; i < 300; i++ {
+		remaining[i] = true
+	}
+	for _, key := range random.Perm(300)[:200] {
+		sortedMap = sortedMap.Remove(key).(*SortedMap)
+		delete(remaining, key)
+		checkRedBlackInvariants(t, sortedMap.root)
+		if sortedMap.Contains(key) {
+			t.Fatalf("removed key %v is still present", key)
+		}
+	}
+	expected := []int{}
+	for i := 0; i < 300; i++ {
+		if remaining[i] {
+			expected = append(expected, i)
+		}
+	}
+	checkSortedKeys(t, sortedMap, expected)
+	if sortedMap.Remove(1000) != sortedMap {
+		t.Fatalf("removing a missing key should return the same map")
+	}
+}
+
+func TestSortedMapFirstLastAndBetween(t *testing.T) {
+	sortedMap := NewSortedMap(5, "e", 1, "a", 3, "c", 9, "i", 7, "g")
+	if sortedMap.First().Key != 1 || sortedMap.Last().Key != 9 {
+		t.Fatalf("unexpected first and last")
+	}
+	between := sortedMap.Between(3, 9).ToSlice()
+	if len(between) != 3 || between[0].(MapEntry).Key != 3 || between[2].(MapEntry).Key != 7 {
+		t.Fatalf("unexpected range %v", between)
+	}
+	if len(sortedMap.Between(4, 5).ToSlice()) != 0 {
+		t.Fatalf("expected an empty range")
+	}
+	shouldPanic(t, func() { EmptySortedMap().First() }, ErrEmptyCollection)
+}
+
+func TestSortedMapIncomparableKeys(t *testing.T) {
+	sortedMap := NewSortedMap(1, "a")
+	shouldPanic(t, func() { sortedMap.Set("b", 2) }, ErrIncomparable)
+}
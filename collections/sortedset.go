@@ -1 +1,123 @@
 package collections
+
+// SortedSet is a persistent set backed by a SortedMap whose keys
+// are the elements of the set. Elements are iterated in order
+
+type SortedSet struct {
+	entries *SortedMap
+}
+
+func EmptySortedSet() *SortedSet {
+	return &SortedSet{entries: EmptySortedMap()}
+}
+
+func NewSortedSet(values ...interface{}) *SortedSet {
+	var set Set = EmptySortedSet()
+	for _, value := range values {
+		set = set.Add(value)
+	}
+	return set.(*SortedSet)
+}
+
+func (set *SortedSet) Size() int {
+	return set.entries.Size()
+}
+
+func (set *SortedSet) Contains(value interface{}) bool {
+	return set.entries.Contains(value)
+}
+
+func (set *SortedSet) Add(value interface{}) Set {
+	if set.Contains(value) {
+		return set
+	}
+	return &SortedSet{entries: set.entries.Set(value, struct{}{}).(*SortedMap)}
+}
+
+func (set *SortedSet) Remove(value interface{}) Set {
+	return &SortedSet{entries: set.entries.Remove(value).(*SortedMap)}
+}
+
+// Returns the smallest element
+func (set *SortedSet) First() interface{} {
+	return set.entries.First().Key
+}
+
+// Returns the largest element
+func (set *SortedSet) Last() interface{} {
+	return set.entries.Last().Key
+}
+
+// Returns the elements from from up to, but not including, to, in order
+func (set *SortedSet) Between(from interface{}, to interface{}) Iterable {
+	return set.entries.Between(from, to).Map(func(entry interface{}) interface{} {
+		return entry.(MapEntry).Key
+	})
+}
+
+func (set *SortedSet) SubsetOf(other Set) bool {
+	if set.Size() > other.Size() {
+		return false
+	}
+	iterator := set.Iterator()
+	for iterator.MoveNext() {
+		if !other.Contains(iterator.Current()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *SortedSet) Union(other Set) Set {
+	var union Set = set
+	iterator := other.Iterator()
+	for iterator.MoveNext() {
+		union = union.Add(iterator.Current())
+	}
+	return union
+}
+
+func (set *SortedSet) Intersect(other Set) Set {
+	var intersection Set = EmptySortedSet()
+	iterator := set.Iterator()
+	for iterator.MoveNext() {
+		value := iterator.Current()
+		if other.Contains(value) {
+			intersection = intersection.Add(value)
+		}
+	}
+	return intersection
+}
+
+func (set *SortedSet) Difference(other Set) Set {
+	var difference Set = set
+	iterator := other.Iterator()
+	for iterator.MoveNext() {
+		difference = difference.Remove(iterator.Current())
+	}
+	return difference
+}
+
+func (set *SortedSet) Iterator() Iterator {
+	return set.entries.Keys().Iterator()
+}
+
+func (set *SortedSet) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(set, mapFn)
+}
+
+func (set *SortedSet) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(set, filterFn)
+}
+
+func (set *SortedSet) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(set, initialValue, reducerFn)
+}
+
+func (set *SortedSet) ForEach(iterFn func(interface{})) {
+	forEachHelper(set, iterFn)
+}
+
+func (set *SortedSet) ToSlice() []interface{} {
+	return toSliceHelper(set)
+}
@@ -0,0 +1,51 @@
+package collections
+
+import "testing"
+
+func TestSortedSetOrdering(t *testing.T) {
+	set := NewSortedSet("pear", "apple", "fig", "apple")
+	values := set.ToSlice()
+	if len(values) != 3 || values[0] != "apple" || values[1] != "fig" || values[2] != "pear" {
+		t.Fatalf("unexpected elements %v", values)
+	}
+	if set.First() != "apple" || set.Last() != "pear" {
+		t.Fatalf("unexpected first and last")
+	}
+	between := set.Between("b", "g").ToSlice()
+	if len(between) != 1 || between[0] != "fig" {
+		t.Fatalf("unexpected range %v", between)
+	}
+}
+
+func TestSortedSetOperations(t *testing.T) {
+	evens := NewSortedSet(0, 2, 4, 6)
+	small := NewSortedSet(0, 1, 2, 3)
+
+	union := evens.Union(small).ToSlice()
+	if len(union) != 6 || union[0] != 0 || union[5] != 6 {
+		t.Fatalf("unexpected union %v", union)
+	}
+	intersection := evens.Intersect(small)
+	if intersection.Size() != 2 || !intersection.Contains(0) || !intersection.Contains(2) {
+		t.Fatalf("unexpected intersection %v", intersection.ToSlice())
+	}
+	difference := evens.Difference(small)
+	if difference.Size() != 2 || !difference.Contains(4) || !difference.Contains(6) {
+		t.Fatalf("unexpected difference %v", difference.ToSlice())
+	}
+	if !intersection.SubsetOf(evens) || evens.SubsetOf(intersection) {
+		t.Fatalf("unexpected subset relationship")
+	}
+}
+
+func TestSortedSetIsPersistent(t *testing.T) {
+	original := NewSortedSet(1, 2)
+	added := original.Add(3)
+	removed := original.Remove(1)
+	if original.Size() != 2 || original.Contains(3) || !original.Contains(1) {
+		t.Fatalf("original set was mutated")
+	}
+	if added.Size() != 3 || removed.Size() != 1 {
+		t.Fatalf("unexpected sizes %v and %v", added.Size(), removed.Size())
+	}
+}
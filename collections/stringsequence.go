@@ -1 +1,106 @@
 package collections
+
+// StringSequence is an immutable string indexed by rune rather than
+// by byte. Elements are runes, and setting an element to anything
+// else panics with ErrNotARune. Every update copies the string, so
+// you can expect:
+// Random access (O(1))
+// Functional update, append, prepend, slice and concat (O(n))
+
+type StringSequence struct {
+	runes []rune
+}
+
+func NewStringSequence(s string) *StringSequence {
+	return &StringSequence{runes: []rune(s)}
+}
+
+func toRune(value interface{}) rune {
+	r, ok := value.(rune)
+	if !ok {
+		panic(ErrNotARune)
+	}
+	return r
+}
+
+func (sequence *StringSequence) String() string {
+	return string(sequence.runes)
+}
+
+func (sequence *StringSequence) Size() int {
+	return len(sequence.runes)
+}
+
+func (sequence *StringSequence) Get(index int) interface{} {
+	if index < 0 || index >= len(sequence.runes) {
+		panic(ErrIndexOutOfRange)
+	}
+	return sequence.runes[index]
+}
+
+func (sequence *StringSequence) Update(index int, value interface{}) Sequence {
+	if index < 0 || index >= len(sequence.runes) {
+		panic(ErrIndexOutOfRange)
+	}
+	runes := make([]rune, len(sequence.runes))
+	copy(runes, sequence.runes)
+	runes[index] = toRune(value)
+	return &StringSequence{runes: runes}
+}
+
+func (sequence *StringSequence) Append(value interface{}) Sequence {
+	runes := make([]rune, len(sequence.runes), len(sequence.runes)+1)
+	copy(runes, sequence.runes)
+	return &StringSequence{runes: append(runes, toRune(value))}
+}
+
+func (sequence *StringSequence) Prepend(value interface{}) Sequence {
+	runes := make([]rune, 0, len(sequence.runes)+1)
+	runes = append(runes, toRune(value))
+	return &StringSequence{runes: append(runes, sequence.runes...)}
+}
+
+// Slices share the original's runes, which is safe because
+// sequences never modify their runes in place
+func (sequence *StringSequence) Slice(start int, end int) Sequence {
+	if start < 0 || end > len(sequence.runes) || start > end {
+		panic(ErrIndexOutOfRange)
+	}
+	return &StringSequence{runes: sequence.runes[start:end:end]}
+}
+
+func (sequence *StringSequence) Concat(other Sequence) Sequence {
+	runes := make([]rune, len(sequence.runes), len(sequence.runes)+other.Size())
+	copy(runes, sequence.runes)
+	if otherString, ok := other.(*StringSequence); ok {
+		return &StringSequence{runes: append(runes, otherString.runes...)}
+	}
+	for i := 0; i < other.Size(); i++ {
+		runes = append(runes, toRune(other.Get(i)))
+	}
+	return &StringSequence{runes: runes}
+}
+
+func (sequence *StringSequence) Iterator() Iterator {
+	return NewSequenceIterator(sequence)
+}
+
+func (sequence *StringSequence) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(sequence, mapFn)
+}
+
+func (sequence *StringSequence) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(sequence, filterFn)
+}
+
+func (sequence *StringSequence) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(sequence, initialValue, reducerFn)
+}
+
+func (sequence *StringSequence) ForEach(iterFn func(interface{})) {
+	forEachHelper(sequence, iterFn)
+}
+
+func (sequence *StringSequence) ToSlice() []interface{} {
+	return toSliceHelper(sequence)
+}
@@ -0,0 +1,49 @@
+package collections
+
+import "testing"
+
+func TestStringSequenceIndexesByRune(t *testing.T) {
+	sequence := NewStringSequence("héllo")
+	if sequence.Size() != 5 {
+		t.Fatalf("expected 5 runes, got %v", sequence.Size())
+	}
+	if sequence.Get(1) != 'é' {
+		t.Fatalf("expected é, got %v", sequence.Get(1))
+	}
+	shouldPanic(t, func() { sequence.Get(5) }, ErrIndexOutOfRange)
+}
+
+func TestStringSequenceIsPersistent(t *testing.T) {
+	sequence := NewStringSequence("cat")
+	updated := sequence.Update(0, 'b').(*StringSequence)
+	appended := sequence.Append('s').(*StringSequence)
+	prepended := sequence.Prepend('s').(*StringSequence)
+	if sequence.String() != "cat" {
+		t.Fatalf("original sequence was mutated")
+	}
+	if updated.String() != "bat" || appended.String() != "cats" || prepended.String() != "scat" {
+		t.Fatalf("unexpected results %v, %v, %v", updated, appended, prepended)
+	}
+	shouldPanic(t, func() { sequence.Append("s") }, ErrNotARune)
+}
+
+func TestStringSequenceSliceAndConcat(t *testing.T) {
+	sequence := NewStringSequence("ünïcode")
+	sliced := sequence.Slice(1, 4).(*StringSequence)
+	if sliced.String() != "nïc" {
+		t.Fatalf("expected nïc, got %v", sliced)
+	}
+	// Appending to a slice must not overwrite the original
+	sliced.Append('x')
+	if sequence.String() != "ünïcode" {
+		t.Fatalf("original sequence was mutated")
+	}
+	concatenated := sliced.Concat(NewStringSequence("!")).(*StringSequence)
+	if concatenated.String() != "nïc!" {
+		t.Fatalf("expected nïc!, got %v", concatenated)
+	}
+	mixed := sliced.Concat(NewVector('?')).(*StringSequence)
+	if mixed.String() != "nïc?" {
+		t.Fatalf("expected nïc?, got %v", mixed)
+	}
+}
@@ -10,6 +10,10 @@ package collections
 // Append and prepend (amortirzed O(1))
 //
 // Along with excellent memory sharing.
+//
+// Scala specializes vectors of every depth up to Vector6. Here Vector0,
+// Vector1 and Vector2 are ported directly, and VectorN, a generalized
+// radix trie, handles everything larger.
 
 const bits = 5
 const width = 32
@@ -27,7 +31,7 @@ const mask = 31
 const lastWidth = 64
 const log2ConcatFactor = 5
 
-const MaxVectorSize = width2
+const MaxVectorSize = width6
 
 type Arr1 []interface{}
 
@@ -37,6 +41,14 @@ type Vector interface {
 	Sequence
 }
 
+// Appends to a copy of an array, so that vectors sharing
+// the original array are not affected
+func appendCopy(data Arr1, value interface{}) Arr1 {
+	newData := make(Arr1, len(data), len(data)+1)
+	copy(newData, data)
+	return append(newData, value)
+}
+
 type Vector0 struct{}
 
 func EmptyVector() Vector {
@@ -57,6 +69,19 @@ func (vector0 *Vector0) Update(index int, value interface{}) Sequence {
 	panic(ErrVectorIndexOutOfRange)
 }
 
+func (vector0 *Vector0) Prepend(value interface{}) Sequence {
+	return vector0.Append(value)
+}
+
+func (vector0 *Vector0) Slice(start int, end int) Sequence {
+	checkSliceBounds(start, end, 0)
+	return vector0
+}
+
+func (vector0 *Vector0) Concat(other Sequence) Sequence {
+	return other
+}
+
 func (vector0 *Vector0) String() string {
 	return "<>"
 }
@@ -104,7 +129,7 @@ func (vector1 *Vector1) Append(value interface{}) Sequence {
 	length := len(vector1.data)
 	if length < width {
 		return &Vector1{
-			data: append(vector1.data, value),
+			data: appendCopy(vector1.data, value),
 		}
 	}
 
@@ -121,6 +146,34 @@ func (vector1 *Vector1) Size() int {
 	return len(vector1.data)
 }
 
+func (vector1 *Vector1) Prepend(value interface{}) Sequence {
+	length := len(vector1.data)
+	if length < width {
+		newData := make(Arr1, 0, length+1)
+		newData = append(newData, value)
+		return &Vector1{
+			data: append(newData, vector1.data...),
+		}
+	}
+
+	return &Vector2{
+		prefix1:       Arr1{value},
+		data2:         Arr2{},
+		suffix1:       vector1.data,
+		length:        width + 1,
+		prefix1Length: 1,
+	}
+}
+
+func (vector1 *Vector1) Slice(start int, end int) Sequence {
+	checkSliceBounds(start, end, len(vector1.data))
+	return vectorFromSlice(vector1.data[start:end])
+}
+
+func (vector1 *Vector1) Concat(other Sequence) Sequence {
+	return concatSequences(vector1, other)
+}
+
 func (vector1 *Vector1) Update(index int, value interface{}) Sequence {
 	length := len(vector1.data)
 	if index < 0 || index >= length {
@@ -184,7 +237,7 @@ func (vector2 *Vector2) Get(index int) interface{} {
 
 func (vector2 *Vector2) Append(value interface{}) Sequence {
 	if len(vector2.suffix1) < width {
-		newSuffix1 := append(vector2.suffix1, value)
+		newSuffix1 := appendCopy(vector2.suffix1, value)
 		return &Vector2{
 			prefix1:       vector2.prefix1,
 			data2:         vector2.data2,
@@ -194,7 +247,9 @@ func (vector2 *Vector2) Append(value interface{}) Sequence {
 		}
 	}
 	if len(vector2.data2) < width-2 {
-		newData2 := append(vector2.data2, vector2.suffix1)
+		newData2 := make(Arr2, len(vector2.data2), len(vector2.data2)+1)
+		copy(newData2, vector2.data2)
+		newData2 = append(newData2, vector2.suffix1)
 		newSuffix1 := Arr1{value}
 		return &Vector2{
 			prefix1:       vector2.prefix1,
@@ -204,7 +259,45 @@ func (vector2 *Vector2) Append(value interface{}) Sequence {
 			prefix1Length: vector2.prefix1Length,
 		}
 	}
-	panic(ErrVectorTooLarge)
+	return newVectorN(append(vector2.ToSlice(), value))
+}
+
+func (vector2 *Vector2) Prepend(value interface{}) Sequence {
+	if vector2.prefix1Length < width {
+		newPrefix1 := make(Arr1, 0, vector2.prefix1Length+1)
+		newPrefix1 = append(newPrefix1, value)
+		newPrefix1 = append(newPrefix1, vector2.prefix1...)
+		return &Vector2{
+			prefix1:       newPrefix1,
+			data2:         vector2.data2,
+			suffix1:       vector2.suffix1,
+			length:        vector2.length + 1,
+			prefix1Length: vector2.prefix1Length + 1,
+		}
+	}
+	// The prefix is full, so it becomes the first block of data2
+	if len(vector2.data2) < width-2 {
+		newData2 := make(Arr2, 0, len(vector2.data2)+1)
+		newData2 = append(newData2, vector2.prefix1)
+		newData2 = append(newData2, vector2.data2...)
+		return &Vector2{
+			prefix1:       Arr1{value},
+			data2:         newData2,
+			suffix1:       vector2.suffix1,
+			length:        vector2.length + 1,
+			prefix1Length: 1,
+		}
+	}
+	return newVectorN(append(Arr1{value}, vector2.ToSlice()...))
+}
+
+func (vector2 *Vector2) Slice(start int, end int) Sequence {
+	checkSliceBounds(start, end, vector2.length)
+	return vectorFromSlice(vector2.ToSlice()[start:end])
+}
+
+func (vector2 *Vector2) Concat(other Sequence) Sequence {
+	return concatSequences(vector2, other)
 }
 
 func (vector2 *Vector2) Size() int {
@@ -213,7 +306,7 @@ func (vector2 *Vector2) Size() int {
 
 func (vector2 *Vector2) Update(index int, value interface{}) Sequence {
 	// Handle index out of range
-	if index < 0 || index > vector2.length {
+	if index < 0 || index >= vector2.length {
 		panic(ErrVectorIndexOutOfRange)
 	}
 
@@ -290,3 +383,288 @@ func (vector *Vector2) ForEach(iterFn func(interface{})) {
 func (vector *Vector2) ToSlice() []interface{} {
 	return toSliceHelper(vector)
 }
+
+// VectorN is a radix trie of any depth, used for vectors too large for
+// Vector2. Interior nodes are Arr1s of up to width children and leaves
+// are Arr1s of up to width elements. The elements occupy the contiguous
+// trie indices [offset, offset+length). Leaving room before the first
+// element lets prepends fill the trie from the left, just as appends
+// fill it from the right
+type VectorN struct {
+	root Arr1
+	// The number of index bits consumed below the root. The root
+	// covers 1 << (shift + bits) trie indices
+	shift  uint
+	offset int
+	length int
+}
+
+// Builds a VectorN holding the given elements. The trie is built a
+// level at a time, so this takes O(n)
+func newVectorN(elements []interface{}) *VectorN {
+	if len(elements) > MaxVectorSize {
+		panic(ErrVectorTooLarge)
+	}
+	nodes := []Arr1{}
+	for start := 0; start < len(elements); start += width {
+		leaf := make(Arr1, width)
+		copy(leaf, elements[start:])
+		nodes = append(nodes, leaf)
+	}
+	shift := uint(0)
+	for len(nodes) > 1 {
+		parents := []Arr1{}
+		for start := 0; start < len(nodes); start += width {
+			parent := make(Arr1, width)
+			for i, node := range nodes[start:minInt(start+width, len(nodes))] {
+				parent[i] = node
+			}
+			parents = append(parents, parent)
+		}
+		nodes = parents
+		shift += bits
+	}
+	root := make(Arr1, width)
+	if len(nodes) == 1 {
+		root = nodes[0]
+	}
+	return &VectorN{
+		root:   root,
+		shift:  shift,
+		offset: 0,
+		length: len(elements),
+	}
+}
+
+func (vector *VectorN) capacity() int {
+	return 1 << (vector.shift + bits)
+}
+
+func (vector *VectorN) Get(index int) interface{} {
+	if index < 0 || index >= vector.length {
+		panic(ErrVectorIndexOutOfRange)
+	}
+	trieIndex := vector.offset + index
+	node := vector.root
+	for level := vector.shift; level > 0; level -= bits {
+		node = node[(trieIndex>>level)&mask].(Arr1)
+	}
+	return node[trieIndex&mask]
+}
+
+// Returns a copy of node with the trie index set to value, copying
+// only the nodes on the path to it. Missing nodes are created
+func setInTrie(node Arr1, level uint, trieIndex int, value interface{}) Arr1 {
+	newNode := make(Arr1, width)
+	copy(newNode, node)
+	slot := (trieIndex >> level) & mask
+	if level == 0 {
+		newNode[slot] = value
+		return newNode
+	}
+	child, _ := newNode[slot].(Arr1)
+	newNode[slot] = setInTrie(child, level-bits, trieIndex, value)
+	return newNode
+}
+
+func (vector *VectorN) Update(index int, value interface{}) Sequence {
+	if index < 0 || index >= vector.length {
+		panic(ErrVectorIndexOutOfRange)
+	}
+	return &VectorN{
+		root:   setInTrie(vector.root, vector.shift, vector.offset+index, value),
+		shift:  vector.shift,
+		offset: vector.offset,
+		length: vector.length,
+	}
+}
+
+func (vector *VectorN) Append(value interface{}) Sequence {
+	if vector.length >= MaxVectorSize {
+		panic(ErrVectorTooLarge)
+	}
+	root, shift := vector.root, vector.shift
+	trieIndex := vector.offset + vector.length
+	if trieIndex >= vector.capacity() {
+		// The trie is full on the right, so the current root
+		// becomes the leftmost child of a new root
+		root = make(Arr1, width)
+		root[0] = vector.root
+		shift += bits
+	}
+	return &VectorN{
+		root:   setInTrie(root, shift, trieIndex, value),
+		shift:  shift,
+		offset: vector.offset,
+		length: vector.length + 1,
+	}
+}
+
+func (vector *VectorN) Prepend(value interface{}) Sequence {
+	if vector.length >= MaxVectorSize {
+		panic(ErrVectorTooLarge)
+	}
+	root, shift, offset := vector.root, vector.shift, vector.offset
+	if offset == 0 {
+		// The trie is full on the left, so the current root
+		// becomes the rightmost child of a new root
+		root = make(Arr1, width)
+		root[mask] = vector.root
+		offset += mask * vector.capacity()
+		shift += bits
+	}
+	return &VectorN{
+		root:   setInTrie(root, shift, offset-1, value),
+		shift:  shift,
+		offset: offset - 1,
+		length: vector.length + 1,
+	}
+}
+
+// Slices share the trie of the original vector. Levels of the trie
+// above the slice's elements are dropped
+func (vector *VectorN) Slice(start int, end int) Sequence {
+	checkSliceBounds(start, end, vector.length)
+	if end-start <= width2 {
+		slice := make([]interface{}, 0, end-start)
+		for i := start; i < end; i++ {
+			slice = append(slice, vector.Get(i))
+		}
+		return vectorFromSlice(slice)
+	}
+	root, shift := vector.root, vector.shift
+	first, last := vector.offset+start, vector.offset+end-1
+	for shift > 0 && (first>>shift)&mask == (last>>shift)&mask {
+		child := (first >> shift) & mask
+		root = root[child].(Arr1)
+		first -= child << shift
+		last -= child << shift
+		shift -= bits
+	}
+	return &VectorN{
+		root:   root,
+		shift:  shift,
+		offset: first,
+		length: end - start,
+	}
+}
+
+func (vector *VectorN) Concat(other Sequence) Sequence {
+	return concatSequences(vector, other)
+}
+
+func (vector *VectorN) Size() int {
+	return vector.length
+}
+
+func (vector *VectorN) Iterator() Iterator {
+	return NewSequenceIterator(vector)
+}
+
+func (vector *VectorN) Map(mapFn func(interface{}) interface{}) Iterable {
+	return mapHelper(vector, mapFn)
+}
+
+func (vector *VectorN) Filter(filterFn func(interface{}) bool) Iterable {
+	return filterHelper(vector, filterFn)
+}
+
+func (vector *VectorN) Fold(initialValue interface{}, reducerFn func(interface{}, interface{}) interface{}) interface{} {
+	return foldHelper(vector, initialValue, reducerFn)
+}
+
+func (vector *VectorN) ForEach(iterFn func(interface{})) {
+	forEachHelper(vector, iterFn)
+}
+
+func (vector *VectorN) ToSlice() []interface{} {
+	return toSliceHelper(vector)
+}
+
+// Builds the smallest kind of vector that holds the given elements
+func vectorFromSlice(elements []interface{}) Vector {
+	length := len(elements)
+	if length == 0 {
+		return EmptyVector()
+	}
+	if length <= width {
+		data := make(Arr1, length)
+		copy(data, elements)
+		return &Vector1{data: data}
+	}
+	if length > width2 {
+		return newVectorN(elements)
+	}
+	prefix1 := make(Arr1, width)
+	copy(prefix1, elements)
+	blocks := Arr2{}
+	for start := width; start < length; start += width {
+		block := make(Arr1, minInt(width, length-start))
+		copy(block, elements[start:])
+		blocks = append(blocks, block)
+	}
+	return &Vector2{
+		prefix1:       prefix1,
+		data2:         blocks[:len(blocks)-1],
+		suffix1:       blocks[len(blocks)-1],
+		length:        length,
+		prefix1Length: width,
+	}
+}
+
+// Builds a vector from a slice of elements
+func NewVector(elements ...interface{}) Vector {
+	return vectorFromSlice(elements)
+}
+
+// Concatenates two sequences. When one side is much smaller than the
+// other (by a factor of 1 << log2ConcatFactor) its elements are appended
+// or prepended one at a time to the larger side, sharing the larger
+// side's structure. Otherwise a new vector is built from both
+func concatSequences(left Sequence, right Sequence) Sequence {
+	leftSize, rightSize := left.Size(), right.Size()
+	if leftSize+rightSize > MaxVectorSize {
+		panic(ErrVectorTooLarge)
+	}
+	if rightSize == 0 {
+		return left
+	}
+	if leftSize == 0 {
+		return right
+	}
+	if rightSize < leftSize>>log2ConcatFactor {
+		result := left
+		for i := 0; i < rightSize; i++ {
+			result = result.Append(right.Get(i))
+		}
+		return result
+	}
+	if leftSize < rightSize>>log2ConcatFactor {
+		result := right
+		for i := leftSize - 1; i >= 0; i-- {
+			result = result.Prepend(left.Get(i))
+		}
+		return result
+	}
+	elements := make([]interface{}, 0, leftSize+rightSize)
+	for i := 0; i < leftSize; i++ {
+		elements = append(elements, left.Get(i))
+	}
+	for i := 0; i < rightSize; i++ {
+		elements = append(elements, right.Get(i))
+	}
+	return vectorFromSlice(elements)
+}
+
+func checkSliceBounds(start int, end int, length int) {
+	if start < 0 || end > length || start > end {
+		panic(ErrVectorIndexOutOfRange)
+	}
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -33,7 +33,7 @@ func buildVectorSlice() []Vector {
 	vectors := []Vector{}
 	vector := EmptyVector()
 	vectors = append(vectors, vector)
-	for i := 0; i < MaxVectorSize; i++ {
+	for i := 0; i < width2; i++ {
 		vector = vector.Append(i)
 		vectors = append(vectors, vector)
 	}
@@ -152,5 +152,129 @@ func TestVector2(t *testing.T) {
 		}
 	}
 
-	shouldPanic(t, func() { vector.Append(1024) }, ErrVectorTooLarge)
+	// Vectors keep growing past the size of a Vector2
+	vector = vector.Append(1024)
+	if vector.Size() != 1025 || vector.Get(1024) != 1024 || vector.Get(0) != 0 {
+		t.Fatalf("unexpected vector after growing past 1024 elements")
+	}
+}
+
+// Checks that a vector holds the ints from first to first+size-1
+func checkVectorContents(t *testing.T, vector Sequence, first int, size int) {
+	t.Helper()
+	if vector.Size() != size {
+		t.Fatalf("expected vector of size %v, got %v", size, vector.Size())
+	}
+	for i := 0; i < size; i++ {
+		if vector.Get(i) != first+i {
+			t.Fatalf("expected %v at index %v, got %v", first+i, i, vector.Get(i))
+		}
+	}
+}
+
+func TestLargeVectorAppend(t *testing.T) {
+	var vector Sequence = EmptyVector()
+	for i := 0; i < 4*width3+width; i++ {
+		vector = vector.Append(i)
+	}
+	checkVectorContents(t, vector, 0, 4*width3+width)
+	shouldPanic(t, func() { vector.Get(4*width3 + width) }, ErrVectorIndexOutOfRange)
+}
+
+func TestLargeVectorUpdateIsPersistent(t *testing.T) {
+	var vector Sequence = EmptyVector()
+	for i := 0; i < width3+5; i++ {
+		vector = vector.Append(i)
+	}
+	for _, index := range []int{0, 31, 32, 1023, 1024, width3, width3 + 4} {
+		updated := vector.Update(index, "updated")
+		if updated.Get(index) != "updated" {
+			t.Fatalf("expected update at index %v", index)
+		}
+		if vector.Get(index) != index {
+			t.Fatalf("immutable vector was secretly updated at index %v", index)
+		}
+	}
+}
+
+func TestVectorPrepend(t *testing.T) {
+	var vector Sequence = EmptyVector()
+	for i := width3 + 100; i > 0; i-- {
+		vector = vector.Prepend(i - 1)
+		if i%997 == 0 {
+			checkVectorContents(t, vector, i-1, width3+101-i)
+		}
+	}
+	checkVectorContents(t, vector, 0, width3+100)
+
+	// Appends and prepends can be mixed
+	vector = vector.Prepend(-1).Append(width3 + 100)
+	checkVectorContents(t, vector, -1, width3+102)
+}
+
+func TestVectorSlice(t *testing.T) {
+	elements := make([]interface{}, width3)
+	for i := range elements {
+		elements[i] = i
+	}
+	vector := NewVector(elements...)
+	for _, bounds := range [][2]int{{0, 0}, {0, 10}, {5, 40}, {100, 1100}, {31, 2000}, {width2, width3}, {0, width3}} {
+		start, end := bounds[0], bounds[1]
+		slice := vector.Slice(start, end)
+		checkVectorContents(t, slice, start, end-start)
+		// Slices can still grow in both directions
+		checkVectorContents(t, slice.Append(end).Prepend(start-1), start-1, end-start+2)
+	}
+	shouldPanic(t, func() { vector.Slice(-1, 3) }, ErrVectorIndexOutOfRange)
+	shouldPanic(t, func() { vector.Slice(3, 2) }, ErrVectorIndexOutOfRange)
+	shouldPanic(t, func() { vector.Slice(0, width3+1) }, ErrVectorIndexOutOfRange)
+}
+
+func TestVectorConcat(t *testing.T) {
+	build := func(first int, size int) Sequence {
+		elements := make([]interface{}, size)
+		for i := range elements {
+			elements[i] = first + i
+		}
+		return NewVector(elements...)
+	}
+	for _, sizes := range [][2]int{{0, 5}, {5, 0}, {3, 4}, {2000, 10}, {10, 2000}, {1500, 1500}, {30, 40000}} {
+		left := build(0, sizes[0])
+		right := build(sizes[0], sizes[1])
+		checkVectorContents(t, left.Concat(right), 0, sizes[0]+sizes[1])
+		// Neither side is modified
+		checkVectorContents(t, left, 0, sizes[0])
+		checkVectorContents(t, right, sizes[0], sizes[1])
+	}
+}
+
+func TestVectorAppendDoesNotShareStructure(t *testing.T) {
+	vectors := buildVectorSlice()
+	for i := 0; i < len(vectors)-1; i++ {
+		original := vectors[i]
+		// Vectors built by appending to the same vector must not
+		// see each other's elements
+		first := original.Append("first")
+		second := original.Append("second")
+		if first.Get(i) != "first" || second.Get(i) != "second" {
+			t.Fatalf("appends to a vector of length %v interfered with each other", i)
+		}
+	}
+}
+
+func TestVectorIterator(t *testing.T) {
+	vectors := buildVectorSlice()
+	for i := 0; i < len(vectors); i++ {
+		iterator := vectors[i].(Iterable).Iterator()
+		count := 0
+		for iterator.MoveNext() {
+			if iterator.Current() != count {
+				t.Fatalf("expected %v at position %v of iteration, got %v", count, count, iterator.Current())
+			}
+			count++
+		}
+		if count != i {
+			t.Fatalf("expected iteration over %v elements, got %v", i, count)
+		}
+	}
 }
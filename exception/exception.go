@@ -1,7 +1,15 @@
 package exception
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
+// Exception is the error type returned throughout the lexer, parser
+// and interpreter. Exceptions raised by Otter itself are always
+// *OtterException values, which can be recovered with errors.As
+// or the As helper in this package.
 type Exception error
 
 type ExceptionType string
@@ -18,13 +26,142 @@ const (
 	MethodError       ExceptionType = "MethodError"
 	ArgumentError     ExceptionType = "ArgumentError"
 	IndexError        ExceptionType = "IndexError"
+	KeyError          ExceptionType = "KeyError"
 	IterationError    ExceptionType = "IterationError"
+	ImportError       ExceptionType = "ImportError"
+	// The most general kind of exception. Catching BaseException
+	// catches every exception
+	BaseException ExceptionType = "Exception"
 )
 
+// An OtterException is a structured exception value. It records
+// the kind of exception, a human readable message and where in
+// the source the exception was raised.
+type OtterException struct {
+	// The kind of exception, used to distinguish e.g. a TypeError
+	// from an IndexError
+	Type ExceptionType
+	// A human readable description of what went wrong
+	Message string
+	// The line at which the exception was raised, or 0 if unknown
+	Line int
+	// The column at which the exception was raised, or 0 if unknown
+	Col int
+	// The name of the source file, if known
+	FileName string
+	// The underlying error that caused this exception, if any
+	Cause error
+	// The call stack at the point the exception was raised, outermost
+	// frame first. Nil until the interpreter records it
+	StackTrace []StackFrame
+}
+
+// A StackFrame records the position executing in one function
+// call when an exception was raised
+type StackFrame struct {
+	FunctionName string
+	FileName     string
+	Line         int
+	Col          int
+}
+
+func (e *OtterException) Error() string {
+	if e.FileName != "" {
+		return fmt.Sprintf("%v: %v at %v:%v:%v", e.Type, e.Message, e.FileName, e.Line, e.Col)
+	}
+	return fmt.Sprintf("%v: %v at %v:%v", e.Type, e.Message, e.Line, e.Col)
+}
+
+// Renders the exception as a Python style traceback, listing the
+// call stack from the outermost call to the point the exception
+// was raised
+func (e *OtterException) Traceback() string {
+	var builder strings.Builder
+	if len(e.StackTrace) > 0 {
+		builder.WriteString("Traceback (most recent call last):\n")
+		for _, frame := range e.StackTrace {
+			fileName := frame.FileName
+			if fileName == "" {
+				fileName = "<unknown>"
+			}
+			builder.WriteString(fmt.Sprintf("  File \"%v\", line %v, col %v, in %v\n", fileName, frame.Line, frame.Col, frame.FunctionName))
+		}
+	}
+	builder.WriteString(e.Error())
+	return builder.String()
+}
+
+// Allows errors.Is and errors.As to inspect the cause
+// of an exception
+func (e *OtterException) Unwrap() error {
+	return e.Cause
+}
+
 func New(
 	exceptionType ExceptionType,
 	message string,
 	line int,
 	col int) Exception {
-	return fmt.Errorf("%v: %v at %v:%v", exceptionType, message, line, col)
+	return &OtterException{
+		Type:    exceptionType,
+		Message: message,
+		Line:    line,
+		Col:     col,
+	}
+}
+
+// Creates a new exception caused by another error. The cause's message
+// is used if no message is provided
+func Wrap(
+	exceptionType ExceptionType,
+	message string,
+	cause error,
+	line int,
+	col int) Exception {
+	if message == "" && cause != nil {
+		message = cause.Error()
+	}
+	return &OtterException{
+		Type:    exceptionType,
+		Message: message,
+		Line:    line,
+		Col:     col,
+		Cause:   cause,
+	}
+}
+
+// Returns the OtterException in err's chain, if there is one
+func As(err error) (*OtterException, bool) {
+	var otterException *OtterException
+	if errors.As(err, &otterException) {
+		return otterException, true
+	}
+	return nil, false
+}
+
+// Returns the type of the exception in err's chain. Errors that
+// did not originate in Otter are reported as InternalErrors
+func TypeOf(err error) ExceptionType {
+	if otterException, ok := As(err); ok {
+		return otterException.Type
+	}
+	return InternalError
+}
+
+// Renders err with a traceback if it is an OtterException
+// carrying a stack trace
+func Format(err error) string {
+	if otterException, ok := As(err); ok {
+		return otterException.Traceback()
+	}
+	return err.Error()
+}
+
+// Records the source file an exception was raised in, if it is
+// not already known
+func WithFileName(err error, fileName string) Exception {
+	if otterException, ok := As(err); ok && otterException.FileName == "" {
+		otterException.FileName = fileName
+	}
+	return err
 }
@@ -0,0 +1,68 @@
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestExceptionAs(t *testing.T) {
+	err := New(TypeError, "bad operand", 3, 7)
+	wrapped := fmt.Errorf("while running: %w", err)
+
+	otterException, ok := As(wrapped)
+	if !ok {
+		t.Fatalf("expected %v to contain an OtterException", wrapped)
+	}
+	if otterException.Type != TypeError || otterException.Line != 3 || otterException.Col != 7 {
+		t.Fatalf("unexpected exception %#v", otterException)
+	}
+	if TypeOf(wrapped) != TypeError {
+		t.Fatalf("expected TypeOf to return %v, got %v", TypeError, TypeOf(wrapped))
+	}
+}
+
+func TestExceptionTypeOfForeignError(t *testing.T) {
+	err := errors.New("not an otter exception")
+	if TypeOf(err) != InternalError {
+		t.Fatalf("expected foreign errors to be InternalErrors, got %v", TypeOf(err))
+	}
+}
+
+func TestExceptionWrapUnwrapsToCause(t *testing.T) {
+	_, cause := strconv.ParseInt("abc", 0, 64)
+	err := Wrap(ArgumentError, "", cause, 1, 1)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected %v to wrap %v", err, strconv.ErrSyntax)
+	}
+	otterException, _ := As(err)
+	if otterException.Message != cause.Error() {
+		t.Fatalf("expected message to default to cause, got %v", otterException.Message)
+	}
+}
+
+func TestExceptionMessage(t *testing.T) {
+	err := WithFileName(New(IndexError, "index out of range", 2, 4), "script.otter")
+	expected := "IndexError: index out of range at script.otter:2:4"
+	if err.Error() != expected {
+		t.Fatalf("expected %v, got %v", expected, err.Error())
+	}
+}
+
+func TestExceptionTraceback(t *testing.T) {
+	err := New(TypeError, "bad operand", 2, 12)
+	otterException, _ := As(err)
+	otterException.FileName = "script.otter"
+	otterException.StackTrace = []StackFrame{
+		{FunctionName: "global", FileName: "script.otter", Line: 4, Col: 2},
+		{FunctionName: "f", FileName: "script.otter", Line: 2, Col: 12},
+	}
+	expected := "Traceback (most recent call last):\n" +
+		"  File \"script.otter\", line 4, col 2, in global\n" +
+		"  File \"script.otter\", line 2, col 12, in f\n" +
+		"TypeError: bad operand at script.otter:2:12"
+	if Format(err) != expected {
+		t.Fatalf("expected\n%v\ngot\n%v", expected, Format(err))
+	}
+}
@@ -2,17 +2,59 @@ package interpreter
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
 )
 
 func ConstructArray(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
 	newSlice := make([]*OtterValue, len(values))
 	copy(newSlice, values)
+	return interpreter.NewArray(newSlice), nil
+}
+
+func (interpreter *Interpreter) NewArray(elements []*OtterValue) *OtterValue {
 	return &OtterValue{
 		Type:  interpreter.MustResolveType(TArray),
-		Value: newSlice,
-	}, nil
+		Value: elements,
+	}
+}
+
+// Converts an Otter index into an index into a slice of the given length.
+// Negative indices count back from the end of the slice
+func normalizeIndex(index *OtterValue, length int) (int, exception.Exception) {
+	if !index.IsInstanceOf(TInt) {
+		return 0, exception.New(exception.ArgumentError, fmt.Sprintf("Array Index must be int, got %v", index.Type.Value), 0, 0)
+	}
+	indexValue := int(index.Value.(int64))
+	if indexValue < 0 {
+		indexValue += length
+	}
+	if indexValue < 0 || indexValue >= length {
+		return 0, exception.New(exception.IndexError, "Array index out of range", 0, 0)
+	}
+	return indexValue, nil
+}
+
+// Converts an Otter slice bound into an index into a slice of the given
+// length. Negative bounds count back from the end of the slice and bounds
+// outside the slice are clamped to it
+func normalizeSliceBound(bound *OtterValue, length int) (int, exception.Exception) {
+	if !bound.IsInstanceOf(TInt) {
+		return 0, exception.New(exception.ArgumentError, fmt.Sprintf("slice bounds must be int, got %v", bound.Type.Value), 0, 0)
+	}
+	boundValue := int(bound.Value.(int64))
+	if boundValue < 0 {
+		boundValue += length
+	}
+	if boundValue < 0 {
+		return 0, nil
+	}
+	if boundValue > length {
+		return length, nil
+	}
+	return boundValue, nil
 }
 
 func ArrayLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
@@ -32,17 +74,298 @@ func ArrayAdd(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exce
 
 func ArrayGetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
 	array := values[0]
-	index := values[1]
-	if !index.IsInstanceOf(TInt) {
-		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("Array Index must be int, got %v", index.Type.Value), 0, 0)
+	underlyingSlice := array.Value.([]*OtterValue)
+	index, err := normalizeIndex(values[1], len(underlyingSlice))
+	if err != nil {
+		return nil, err
 	}
+	return underlyingSlice[index], nil
+}
+
+func ArraySetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	array := values[0]
 	underlyingSlice := array.Value.([]*OtterValue)
-	indexValue := index.Value.(int64)
+	index, err := normalizeIndex(values[1], len(underlyingSlice))
+	if err != nil {
+		return nil, err
+	}
+	underlyingSlice[index] = values[2]
+	return values[2], nil
+}
 
-	if indexValue < 0 || len(underlyingSlice) < int(indexValue) {
-		return nil, exception.New(exception.IndexError, "Array index out of range", 0, 0)
+// Removes and returns the element at the given index, or the
+// last element if no index is given
+func ArrayPop(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values) > 2 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("pop takes at most 1 argument, got %v", len(values)-1), 0, 0)
 	}
-	return underlyingSlice[indexValue], nil
+	array := values[0]
+	underlyingSlice := array.Value.([]*OtterValue)
+	if len(underlyingSlice) == 0 {
+		return nil, exception.New(exception.IndexError, "pop from empty Array", 0, 0)
+	}
+	index := len(underlyingSlice) - 1
+	if len(values) == 2 {
+		var err exception.Exception
+		index, err = normalizeIndex(values[1], len(underlyingSlice))
+		if err != nil {
+			return nil, err
+		}
+	}
+	popped := underlyingSlice[index]
+	array.Value = append(underlyingSlice[:index], underlyingSlice[index+1:]...)
+	return popped, nil
+}
+
+// Inserts a value before the given index. Negative indices count back
+// from the end of the array, and an index equal to the length of the
+// array appends the value
+func ArrayInsert(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	array := values[0]
+	underlyingSlice := array.Value.([]*OtterValue)
+	index := len(underlyingSlice)
+	if !values[1].IsInstanceOf(TInt) || values[1].Value.(int64) != int64(index) {
+		var err exception.Exception
+		index, err = normalizeIndex(values[1], len(underlyingSlice))
+		if err != nil {
+			return nil, err
+		}
+	}
+	newSlice := make([]*OtterValue, 0, len(underlyingSlice)+1)
+	newSlice = append(newSlice, underlyingSlice[:index]...)
+	newSlice = append(newSlice, values[2])
+	newSlice = append(newSlice, underlyingSlice[index:]...)
+	array.Value = newSlice
+	return interpreter.NewNull(), nil
+}
+
+func (interpreter *Interpreter) arrayIndexOf(array *OtterValue, value *OtterValue) (int, exception.Exception) {
+	for index, element := range array.Value.([]*OtterValue) {
+		equal, err := interpreter.valuesEqual(element, value, 0, 0)
+		if err != nil {
+			return 0, err
+		}
+		if equal {
+			return index, nil
+		}
+	}
+	return -1, nil
+}
+
+// Removes the first element equal to the given value. Returns
+// whether an element was removed
+func ArrayRemove(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	array := values[0]
+	index, err := interpreter.arrayIndexOf(array, values[1])
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 {
+		return interpreter.False(), nil
+	}
+	underlyingSlice := array.Value.([]*OtterValue)
+	array.Value = append(underlyingSlice[:index], underlyingSlice[index+1:]...)
+	return interpreter.True(), nil
+}
+
+// Returns a new array containing the elements from start up
+// to, but not including, end. If end is omitted the slice runs
+// to the end of the array
+func ArraySlice(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values) < 2 || len(values) > 3 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("slice takes 1 or 2 arguments, got %v", len(values)-1), 0, 0)
+	}
+	array := values[0]
+	underlyingSlice := array.Value.([]*OtterValue)
+	start, err := normalizeSliceBound(values[1], len(underlyingSlice))
+	if err != nil {
+		return nil, err
+	}
+	end := len(underlyingSlice)
+	if len(values) == 3 {
+		end, err = normalizeSliceBound(values[2], len(underlyingSlice))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if end < start {
+		end = start
+	}
+	newSlice := make([]*OtterValue, end-start)
+	copy(newSlice, underlyingSlice[start:end])
+	return interpreter.NewArray(newSlice), nil
+}
+
+func ArrayIndexOf(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	index, err := interpreter.arrayIndexOf(values[0], values[1])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewInt(int64(index)), nil
+}
+
+func ArrayContains(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	index, err := interpreter.arrayIndexOf(values[0], values[1])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewBool(index >= 0), nil
+}
+
+// Returns a new array with the elements of the array followed by
+// those of another. Overloads +
+func ArrayConcat(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	other := values[1]
+	if !other.IsInstanceOf(TArray) {
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("can only add Array to Array, got %v", other.Type.Value), 0, 0)
+	}
+	underlyingSlice := values[0].Value.([]*OtterValue)
+	otherSlice := other.Value.([]*OtterValue)
+	newSlice := make([]*OtterValue, 0, len(underlyingSlice)+len(otherSlice))
+	newSlice = append(newSlice, underlyingSlice...)
+	newSlice = append(newSlice, otherSlice...)
+	return interpreter.NewArray(newSlice), nil
+}
+
+// Builds the < or > method of arrays, which compare elementwise
+func arrayComparison(methodName string, lessThan bool) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		other := values[1]
+		if !other.IsInstanceOf(TArray) {
+			return nil, exception.New(exception.TypeError, fmt.Sprintf("%v expects an Array, got %v", methodName, other.Type.Value), 0, 0)
+		}
+		comparison, err := interpreter.compareSequences(values[0].Value.([]*OtterValue), other.Value.([]*OtterValue))
+		if err != nil {
+			return nil, err
+		}
+		if lessThan {
+			return interpreter.NewBool(comparison < 0), nil
+		}
+		return interpreter.NewBool(comparison > 0), nil
+	}
+}
+
+// Reverses the array in place and returns it
+func ArrayReverse(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	array := values[0]
+	underlyingSlice := array.Value.([]*OtterValue)
+	for i, j := 0, len(underlyingSlice)-1; i < j; i, j = i+1, j-1 {
+		underlyingSlice[i], underlyingSlice[j] = underlyingSlice[j], underlyingSlice[i]
+	}
+	return array, nil
+}
+
+// Compares two values of the same primitive type, returning a negative
+// number, zero or a positive number if left is less than, equal to
+// or greater than right
+func comparePrimitives(left *OtterValue, right *OtterValue) (int, exception.Exception) {
+	switch {
+	case left.IsInstanceOf(TInt) && right.IsInstanceOf(TInt):
+		l, r := left.Value.(int64), right.Value.(int64)
+		if l < r {
+			return -1, nil
+		} else if l > r {
+			return 1, nil
+		}
+		return 0, nil
+	case left.IsInstanceOf(TFloat) && right.IsInstanceOf(TFloat):
+		l, r := left.Value.(float64), right.Value.(float64)
+		if l < r {
+			return -1, nil
+		} else if l > r {
+			return 1, nil
+		}
+		return 0, nil
+	case left.IsInstanceOf(TString) && right.IsInstanceOf(TString):
+		l, r := left.Value.(string), right.Value.(string)
+		if l < r {
+			return -1, nil
+		} else if l > r {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, exception.New(exception.TypeError, fmt.Sprintf("cannot compare %v with %v", left.Type.Value, right.Type.Value), 0, 0)
+}
+
+// Sorts the array in place and returns it. Elements must be ints, floats
+// or strings unless a comparator is given. A comparator is a function
+// of two arguments returning a negative int, zero or a positive int
+// if its first argument is less than, equal to or greater than its second
+func ArraySort(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values) > 2 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("sort takes at most 1 argument, got %v", len(values)-1), 0, 0)
+	}
+	array := values[0]
+	underlyingSlice := array.Value.([]*OtterValue)
+	compare := comparePrimitives
+	if len(values) == 2 {
+		comparator := values[1]
+		if comparator.Callable == nil {
+			return nil, exception.New(exception.ArgumentError, fmt.Sprintf("sort comparator must be callable, got %v", comparator.Type.Value), 0, 0)
+		}
+		compare = func(left *OtterValue, right *OtterValue) (int, exception.Exception) {
+			result, err := interpreter.invokeCallable(comparator.Callable, []*OtterValue{left, right}, 0, 0)
+			if err != nil {
+				return 0, err
+			}
+			if !result.IsInstanceOf(TInt) {
+				return 0, exception.New(exception.TypeError, fmt.Sprintf("sort comparator must return an int, got %v", result.Type.Value), 0, 0)
+			}
+			return int(result.Value.(int64)), nil
+		}
+	}
+
+	// sort.SliceStable can't fail, so remember the first error
+	// and stop comparing once there is one
+	var sortErr exception.Exception
+	sort.SliceStable(underlyingSlice, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+		comparison, err := compare(underlyingSlice[i], underlyingSlice[j])
+		if err != nil {
+			sortErr = err
+			return false
+		}
+		return comparison < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+	return array, nil
+}
+
+type ArrayIteratorInternals struct {
+	Array *OtterValue
+	Index int
+}
+
+func ArrayIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return &OtterValue{
+		Type: interpreter.MustResolveType(TArrayIterator),
+		Value: &ArrayIteratorInternals{
+			Array: values[0],
+			Index: 0,
+		},
+	}, nil
+}
+
+func ArrayIteratorHasNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*ArrayIteratorInternals)
+	underlyingSlice := internals.Array.Value.([]*OtterValue)
+	return interpreter.NewBool(internals.Index < len(underlyingSlice)), nil
+}
+
+func ArrayIteratorGetNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*ArrayIteratorInternals)
+	underlyingSlice := internals.Array.Value.([]*OtterValue)
+	if internals.Index >= len(underlyingSlice) {
+		return nil, exception.New(exception.IterationError, "iterable has no more elements", 0, 0)
+	}
+	value := underlyingSlice[internals.Index]
+	internals.Index = internals.Index + 1
+	return value, nil
 }
 
 func DefineArrayType(interpreter *Interpreter) {
@@ -50,4 +373,33 @@ func DefineArrayType(interpreter *Interpreter) {
 	interpreter.DefineBuiltinMethod(TArray, "length", 1, ArrayLength)
 	interpreter.DefineBuiltinMethod(TArray, "append", Variadic, ArrayAdd)
 	interpreter.DefineBuiltinMethod(TArray, "getItem", 2, ArrayGetItem)
+	interpreter.DefineBuiltinMethod(TArray, "setItem", 3, ArraySetItem)
+	interpreter.DefineBuiltinMethod(TArray, "pop", Variadic, ArrayPop)
+	interpreter.DefineBuiltinMethod(TArray, "insert", 3, ArrayInsert)
+	interpreter.DefineBuiltinMethod(TArray, "remove", 2, ArrayRemove)
+	interpreter.DefineBuiltinMethod(TArray, "slice", Variadic, ArraySlice)
+	interpreter.DefineBuiltinMethod(TArray, "indexOf", 2, ArrayIndexOf)
+	interpreter.DefineBuiltinMethod(TArray, "contains", 2, ArrayContains)
+	interpreter.DefineBuiltinMethod(TArray, "reverse", 1, ArrayReverse)
+	interpreter.DefineBuiltinMethod(TArray, "sort", Variadic, ArraySort)
+	interpreter.DefineBuiltinMethod(TArray, "iterator", 1, ArrayIterator)
+	interpreter.DefineBuiltinMethod(TArray, "add", 2, ArrayConcat)
+	interpreter.DefineBuiltinMethod(TArray, "lessThan", 2, arrayComparison("lessThan", true))
+	interpreter.DefineBuiltinMethod(TArray, "greaterThan", 2, arrayComparison("greaterThan", false))
+
+	interpreter.DefineType(TArrayIterator, NewBuiltInConstructor(TArrayIterator, 1, ArrayIterator))
+	interpreter.DefineBuiltinMethod(TArrayIterator, "hasNext", 1, ArrayIteratorHasNext)
+	interpreter.DefineBuiltinMethod(TArrayIterator, "getNext", 1, ArrayIteratorGetNext)
+}
+
+func (interpreter *Interpreter) doArrayLiteral(tree *parser.Token) (*OtterValue, exception.Exception) {
+	elements := make([]*OtterValue, 0, len(tree.Children))
+	for _, child := range tree.Children {
+		element, err := interpreter.Evaluate(child)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	return interpreter.NewArray(elements), nil
 }
@@ -12,6 +12,11 @@ type OtterValue struct {
 	Value    interface{}
 	Callable *Callable
 	Methods  map[string]*Callable
+	// The fields of an instance of a user defined class. Nil
+	// for values that can't have fields
+	Fields map[string]*OtterValue
+	// The type a type extends, if any
+	Parent *OtterValue
 }
 
 func (v *OtterValue) String() string {
@@ -25,13 +30,36 @@ type Callable struct {
 	Arity               int
 	UserDefinedFunction *parser.Token
 	BuiltInFunction     BuiltInFunction
+	// The file a user defined function was defined in
+	FileName string
+	// The parameters and body of a user defined function
+	Parameters []*parser.Token
+	Body       *parser.Token
+	// The environment a user defined function was defined in.
+	// Names in the body that are not parameters or locals resolve here
+	Closure *Environment
+	// The local variables of a call to a user defined function
+	locals *layout
+	// Methods take the value they are called on as their first
+	// argument. User defined methods see it as this
+	Method bool
+	// The class a user defined method or class constructor belongs to
+	Class *OtterValue
+	// The compiled body of a user defined function, if it was
+	// defined while running bytecode
+	Chunk *Chunk
+	// The interpreter that created the function. Hashed collections
+	// have no interpreter of their own, so call equals methods with it
+	interpreter *Interpreter
 }
 
 func (left *OtterValue) isEqualTo(right *OtterValue) bool {
 	if left.Type.Value != right.Type.Value {
 		return false
 	}
-	switch left.Type.Value {
+	// Instances of classes that extend a builtin type compare their
+	// builtin values
+	switch left.baseType().Value {
 	case TString, TFloat, TBool, TInt:
 		return left.Value == right.Value
 	case TType:
@@ -41,6 +69,6 @@ func (left *OtterValue) isEqualTo(right *OtterValue) bool {
 	case TNull:
 		return true
 	default:
-		return false
+		return left == right
 	}
 }
@@ -1,20 +1,37 @@
 package interpreter
 
-import "github.com/nicholasbailey/otter/exception"
+import (
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
 
+// Returns true or false. Like null, there is only one of each per
+// interpreter
 func (interpreter *Interpreter) NewBool(x bool) *OtterValue {
-	return &OtterValue{
-		Type:  interpreter.MustResolveType(TBool),
-		Value: x,
+	if x {
+		return interpreter.True()
 	}
+	return interpreter.False()
 }
 
 func (interpreter *Interpreter) False() *OtterValue {
-	return interpreter.NewBool(false)
+	if interpreter.falseValue == nil {
+		interpreter.falseValue = &OtterValue{
+			Type:  interpreter.MustResolveType(TBool),
+			Value: false,
+		}
+	}
+	return interpreter.falseValue
 }
 
 func (interpreter *Interpreter) True() *OtterValue {
-	return interpreter.NewBool(true)
+	if interpreter.trueValue == nil {
+		interpreter.trueValue = &OtterValue{
+			Type:  interpreter.MustResolveType(TBool),
+			Value: true,
+		}
+	}
+	return interpreter.trueValue
 }
 
 func ConstructBool(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
@@ -23,9 +40,9 @@ func ConstructBool(interpreter *Interpreter, values []*OtterValue) (*OtterValue,
 }
 
 func (interpreter *Interpreter) Truthiness(value *OtterValue) *OtterValue {
-	switch value.Type.Value {
+	switch value.baseType().Value {
 	case TBool:
-		return value
+		return interpreter.NewBool(value.Value.(bool))
 	case TString:
 		if value.Value.(string) == "" {
 			return interpreter.False()
@@ -46,9 +63,13 @@ func (interpreter *Interpreter) Truthiness(value *OtterValue) *OtterValue {
 		}
 	case TNull:
 		return interpreter.False()
-	case TFunction:
-		return interpreter.True()
+	case TArray:
+		return interpreter.NewBool(len(value.Value.([]*OtterValue)) > 0)
+	case TMap:
+		return interpreter.NewBool(len(value.Value.(*MapInternals).order) > 0)
+	case TVector, TSet, TPersistentMap, TRange:
+		return interpreter.NewBool(value.Value.(collections.FiniteIterable).Size() > 0)
 	}
-	// TODO - handle error correctly
-	panic("How did we get here")
+	// Functions, types and other objects are always truthy
+	return interpreter.True()
 }
@@ -20,6 +20,12 @@ func Print(interpreter *Interpreter, values []*OtterValue) (*OtterValue, excepti
 			fmt.Print(value.Value.(float64))
 		case TNull:
 			fmt.Print("<null>")
+		default:
+			asString, err := ConstructString(interpreter, []*OtterValue{value})
+			if err != nil {
+				return nil, err
+			}
+			fmt.Print(asString.Value.(string))
 		}
 		fmt.Print(" ")
 	}
@@ -30,7 +36,11 @@ func Print(interpreter *Interpreter, values []*OtterValue) (*OtterValue, excepti
 func AssertEqual(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
 	left := values[0]
 	right := values[1]
-	if left.isEqualTo(right) {
+	equal, err := interpreter.valuesEqual(left, right, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if equal {
 		return interpreter.NewNull(), nil
 	}
 	leftAsString, err := ConstructString(interpreter, []*OtterValue{left})
@@ -58,7 +68,11 @@ func DefineBuiltins(interpreter *Interpreter) {
 	printfn, _ := interpreter.NewBuiltInFunction("print", Variadic, Print)
 	assertEqualFn, _ := interpreter.NewBuiltInFunction("assertEqual", 2, AssertEqual)
 	assertTrueFn, _ := interpreter.NewBuiltInFunction("assertEqual", 1, AssertTrue)
+	assertDeepEqualFn, _ := interpreter.NewBuiltInFunction("assertDeepEqual", 2, AssertDeepEqual)
+	hashFn, _ := interpreter.NewBuiltInFunction("hash", 1, HashFunction)
 	interpreter.DefineGlobal("print", printfn)
 	interpreter.DefineGlobal("assertEqual", assertEqualFn)
 	interpreter.DefineGlobal("assertTrue", assertTrueFn)
+	interpreter.DefineGlobal("assertDeepEqual", assertDeepEqualFn)
+	interpreter.DefineGlobal("hash", hashFn)
 }
@@ -0,0 +1,303 @@
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// An Opcode is a single bytecode operation. The virtual machine keeps
+// a stack of values. Most operations pop their operands from it and
+// push their result, and the comment on each opcode describes its
+// operands A and B
+type Opcode byte
+
+const (
+	// Pushes Constants[A]
+	OpConstant Opcode = iota
+	// Pushes null
+	OpNull
+	// Discards the top of the stack
+	OpPop
+	// Discards the top A values
+	OpDrop
+	// Pushes the value of Variables[A]
+	OpLoadVariable
+	// Assigns the top of the stack to Variables[A], leaving it on
+	// the stack
+	OpStoreVariable
+	// Declares Variables[A] in the current scope, like let or const
+	// does, leaving the value on the stack
+	OpDeclareVariable
+	OpDeclareConstant
+	OpAdd
+	OpSubtract
+	OpMultiply
+	OpDivide
+	OpModulo
+	OpNegate
+	OpEqual
+	OpNotEqual
+	OpLessThan
+	OpGreaterThan
+	OpLessThanOrEqual
+	OpGreaterThanOrEqual
+	OpAnd
+	OpOr
+	OpInstanceOf
+	// Jumps to A
+	OpJump
+	// Pops a value and jumps to A if it is falsy
+	OpJumpIfFalse
+	// Enters a block scope with the local variables Layouts[A], and
+	// leaves it
+	OpPushScope
+	OpPopScope
+	// Pops a value and replaces the value below it, which holds the
+	// result of a loop
+	OpSetResult
+	// Calls the function below A arguments. B names the function
+	// in errors
+	OpCall
+	// Replaces a value with its field or method Names[A]
+	OpGetMember
+	// Calls the method Names[A] with B arguments on the value below
+	// them
+	OpCallMember
+	// Calls the super access Tokens[A] with B arguments
+	OpSuper
+	// Replaces a target and index with target[index]
+	OpGetItem
+	// Replaces a target, index and value with the value, setting
+	// target[index]
+	OpSetItem
+	// Raises a TypeError if the top of the stack has no fields. A
+	// is the field token in Tokens
+	OpCheckFields
+	// Replaces a target and value with the value, setting the field
+	// Names[A] of the target
+	OpSetField
+	// Replaces the top A values with an array of them
+	OpArray
+	// Replaces the top A key value pairs with a map of them
+	OpMap
+	// Pushes the function defined by Tokens[A]
+	OpFunction
+	// Pushes the class defined by Tokens[A]. If B is 1 it replaces
+	// the class's parent on top of the stack
+	OpClass
+	// Pushes the module at the path Names[A], importing it if it
+	// hasn't been imported
+	OpImport
+	// Pushes the export Names[A] of the module on top of the stack
+	OpImportName
+	// Pops a value and returns it from the function
+	OpReturn
+	// Pops an exception and raises it
+	OpThrow
+	// Raises the exception being handled again
+	OpRethrow
+	// Starts a try block whose exceptions are handled at A
+	OpSetupTry
+	// Ends the innermost try block
+	OpPopTry
+	// Jumps to B unless the exception on top of the stack has the
+	// type Names[A]. A is -1 for catch clauses that catch everything
+	OpMatchCatch
+	// Pops an exception, handling it in a new scope with the local
+	// variables Layouts[A], the first of which holds it
+	OpEnterCatch
+	// Ends handling an exception
+	OpLeaveCatch
+	// Pops an exception that wasn't handled and raises it again
+	OpReraise
+)
+
+var opcodeNames = map[Opcode]string{
+	OpConstant:           "CONSTANT",
+	OpNull:               "NULL",
+	OpPop:                "POP",
+	OpDrop:               "DROP",
+	OpLoadVariable:       "LOAD_VARIABLE",
+	OpStoreVariable:      "STORE_VARIABLE",
+	OpDeclareVariable:    "DECLARE_VARIABLE",
+	OpDeclareConstant:    "DECLARE_CONSTANT",
+	OpAdd:                "ADD",
+	OpSubtract:           "SUBTRACT",
+	OpMultiply:           "MULTIPLY",
+	OpDivide:             "DIVIDE",
+	OpModulo:             "MODULO",
+	OpNegate:             "NEGATE",
+	OpEqual:              "EQUAL",
+	OpNotEqual:           "NOT_EQUAL",
+	OpLessThan:           "LESS_THAN",
+	OpGreaterThan:        "GREATER_THAN",
+	OpLessThanOrEqual:    "LESS_THAN_OR_EQUAL",
+	OpGreaterThanOrEqual: "GREATER_THAN_OR_EQUAL",
+	OpAnd:                "AND",
+	OpOr:                 "OR",
+	OpInstanceOf:         "INSTANCE_OF",
+	OpJump:               "JUMP",
+	OpJumpIfFalse:        "JUMP_IF_FALSE",
+	OpPushScope:          "PUSH_SCOPE",
+	OpPopScope:           "POP_SCOPE",
+	OpSetResult:          "SET_RESULT",
+	OpCall:               "CALL",
+	OpGetMember:          "GET_MEMBER",
+	OpCallMember:         "CALL_MEMBER",
+	OpSuper:              "SUPER",
+	OpGetItem:            "GET_ITEM",
+	OpSetItem:            "SET_ITEM",
+	OpCheckFields:        "CHECK_FIELDS",
+	OpSetField:           "SET_FIELD",
+	OpArray:              "ARRAY",
+	OpMap:                "MAP",
+	OpFunction:           "FUNCTION",
+	OpClass:              "CLASS",
+	OpImport:             "IMPORT",
+	OpImportName:         "IMPORT_NAME",
+	OpReturn:             "RETURN",
+	OpThrow:              "THROW",
+	OpRethrow:            "RETHROW",
+	OpSetupTry:           "SETUP_TRY",
+	OpPopTry:             "POP_TRY",
+	OpMatchCatch:         "MATCH_CATCH",
+	OpEnterCatch:         "ENTER_CATCH",
+	OpLeaveCatch:         "LEAVE_CATCH",
+	OpReraise:            "RERAISE",
+}
+
+func (op Opcode) String() string {
+	if name, found := opcodeNames[op]; found {
+		return name
+	}
+	return fmt.Sprintf("OP_%d", byte(op))
+}
+
+type Instruction struct {
+	Op Opcode
+	A  int
+	B  int
+}
+
+// The source position an instruction was compiled from
+type Position struct {
+	Line int
+	Col  int
+}
+
+// A Chunk is the compiled bytecode for a script or the body of a
+// function. Literal values are created once, when the chunk is
+// compiled, and names are referred to by their index in Names
+type Chunk struct {
+	Name         string
+	Instructions []Instruction
+	// Positions[i] is the position of Instructions[i]
+	Positions []Position
+	Constants []*OtterValue
+	Names     []string
+	// The variables names refer to, as worked out by the resolver
+	Variables []*variable
+	// The local variables of the blocks and catch clauses in the
+	// chunk
+	Layouts []*layout
+	// Syntax trees for instructions that need more than a name,
+	// like function and class definitions
+	Tokens []*parser.Token
+	// Whether this is the body of a function, which return can
+	// leave, rather than a script
+	IsFunction bool
+	// The chunks of functions defined in this chunk, in the order
+	// they are defined
+	Functions []*Chunk
+}
+
+// Writes a human readable listing of the chunk and the chunks of
+// the functions defined in it
+func (chunk *Chunk) Disassemble(out io.Writer) {
+	fmt.Fprintf(out, "== %v ==\n", chunk.Name)
+	for i, instruction := range chunk.Instructions {
+		position := chunk.Positions[i]
+		location := fmt.Sprintf("%v:%v", position.Line, position.Col)
+		fmt.Fprintf(out, "%04d %8v  %-22v%v\n", i, location, instruction.Op, chunk.describeOperands(instruction))
+	}
+	for _, function := range chunk.Functions {
+		fmt.Fprintln(out)
+		function.Disassemble(out)
+	}
+}
+
+func (chunk *Chunk) describeOperands(instruction Instruction) string {
+	a, b := instruction.A, instruction.B
+	switch instruction.Op {
+	case OpConstant:
+		return fmt.Sprintf("%v (%v)", a, chunk.describeConstant(chunk.Constants[a]))
+	case OpLoadVariable, OpStoreVariable, OpDeclareVariable, OpDeclareConstant:
+		return fmt.Sprintf("%v (%v)", a, chunk.Variables[a])
+	case OpGetMember, OpSetField, OpImport, OpImportName:
+		return fmt.Sprintf("%v (%v)", a, chunk.Names[a])
+	case OpPushScope, OpEnterCatch:
+		return fmt.Sprintf("%v (%v)", a, strings.Join(chunk.Layouts[a].names, ", "))
+	case OpDrop, OpArray, OpMap, OpJump, OpJumpIfFalse, OpSetupTry:
+		return fmt.Sprintf("%v", a)
+	case OpCall:
+		return fmt.Sprintf("%v (%v)", a, chunk.Names[b])
+	case OpCallMember:
+		return fmt.Sprintf("%v (%v) %v", a, chunk.Names[a], b)
+	case OpSuper:
+		return fmt.Sprintf("%v (super.%v) %v", a, superMethodName(chunk.Tokens[a]), b)
+	case OpCheckFields:
+		return fmt.Sprintf("%v (%v)", a, chunk.Tokens[a].Value)
+	case OpFunction, OpClass:
+		return fmt.Sprintf("%v (%v)", a, definitionName(chunk.Tokens[a]))
+	case OpMatchCatch:
+		if a < 0 {
+			return fmt.Sprintf("* %v", b)
+		}
+		return fmt.Sprintf("%v (%v) %v", a, chunk.Names[a], b)
+	}
+	return ""
+}
+
+func (chunk *Chunk) describeConstant(value *OtterValue) string {
+	if value.IsInstanceOf(TString) {
+		return fmt.Sprintf("%q", value.Value)
+	}
+	return fmt.Sprintf("%v", value.Value)
+}
+
+func superMethodName(tree *parser.Token) string {
+	target := tree.Children[1]
+	if target.Symbol == parser.FunctionInvocation {
+		return target.Children[0].Value
+	}
+	return target.Value
+}
+
+func definitionName(tree *parser.Token) string {
+	if tree.Symbol == parser.FunctionLiteral {
+		return AnonymousFunctionName
+	}
+	return tree.Children[0].Value
+}
+
+// Disassembles statements compiled as a script
+func (interpreter *Interpreter) Disassemble(statements []*parser.Token, out io.Writer) error {
+	if err := interpreter.resolve(statements); err != nil {
+		return err
+	}
+	chunk, err := interpreter.compile("<script>", statements, false)
+	if err != nil {
+		return err
+	}
+	chunk.Disassemble(out)
+	return nil
+}
+
+// Returns the name of a chunk for a function definition, like
+// "fib (line 3)"
+func functionChunkName(tree *parser.Token) string {
+	return fmt.Sprintf("%v (line %v)", definitionName(tree), tree.Line)
+}
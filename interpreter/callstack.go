@@ -7,16 +7,25 @@ import (
 )
 
 type CallStackFrame struct {
-	Scope        Scope
+	// The lexical environment of the code executing in this frame
+	Environment  *Environment
 	FunctionName string
-	ReturnValue  *OtterValue
-	Exception    *exception.Exception
+	// The exception being handled by the catch block currently
+	// executing in this frame, if any
+	Exception *exception.OtterException
+	// The file containing the code executing in this frame
+	FileName string
+	// The line and column in the calling frame at which this
+	// frame's function was called
+	CallLine int
+	CallCol  int
 }
 
-func NewCallStackFrame(name string) *CallStackFrame {
-	scope := NewScope()
+// Creates a frame for a call to a function whose variables are
+// held in the given environment
+func NewCallStackFrame(name string, environment *Environment) *CallStackFrame {
 	return &CallStackFrame{
-		Scope:        scope,
+		Environment:  environment,
 		FunctionName: name,
 	}
 }
@@ -61,19 +70,43 @@ func (s *CallStack) Peek() *CallStackFrame {
 	return back.Value.(*CallStackFrame)
 }
 
-func (s *CallStack) ResolveVariable(variableName string) (*OtterValue, bool) {
-
+// Captures the current call stack for a stack trace. The line and
+// column are the position executing in the innermost frame. Each outer
+// frame's position is the call site of the frame above it
+func (s *CallStack) StackTrace(line int, col int) []exception.StackFrame {
+	trace := []exception.StackFrame{}
 	for e := s.list.Back(); e != nil; e = e.Prev() {
 		stackFrame := e.Value.(*CallStackFrame)
-		value, found := stackFrame.Scope[variableName]
-		if found {
-			return value, true
-		}
+		trace = append(trace, exception.StackFrame{
+			FunctionName: stackFrame.FunctionName,
+			FileName:     stackFrame.FileName,
+			Line:         line,
+			Col:          col,
+		})
+		line = stackFrame.CallLine
+		col = stackFrame.CallCol
 	}
-	return nil, false
+	for i, j := 0, len(trace)-1; i < j; i, j = i+1, j-1 {
+		trace[i], trace[j] = trace[j], trace[i]
+	}
+	return trace
+}
+
+// Resolves a variable in the lexical environment of the
+// current frame
+func (s *CallStack) ResolveVariable(variableName string) (*OtterValue, bool) {
+	return s.Peek().Environment.Resolve(variableName)
+}
+
+// Enters a new block scope holding the given local variables in the
+// current frame
+func (s *CallStack) PushScope(names []string) {
+	frame := s.Peek()
+	frame.Environment = NewLocalEnvironment(frame.Environment, names)
 }
 
-func (s *CallStack) AssignVariable(variableName string, value *OtterValue) error {
-	s.Peek().Scope[variableName] = value
-	return nil
+// Leaves the innermost block scope of the current frame
+func (s *CallStack) PopScope() {
+	frame := s.Peek()
+	frame.Environment = frame.Environment.Parent
 }
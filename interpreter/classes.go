@@ -0,0 +1,275 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// The name methods use to refer to the value they were called on
+const ThisName = "this"
+
+// The name methods use to call the methods of their class's parent
+const SuperName = "super"
+
+// The method called to set up a newly constructed instance
+const InitMethodName = "init"
+
+// Holds the class a method belongs to while it runs, so super knows
+// where to start looking for methods. The ~ keeps it out of reach of
+// user code
+const classVariableName = "~class"
+
+// Evaluates a class statement, defining a type whose methods are the
+// functions defined in the class body. Calling the type creates an
+// instance with no fields and passes its arguments to init
+func (interpreter *Interpreter) doClass(tree *parser.Token) (*OtterValue, exception.Exception) {
+	var parent *OtterValue
+	if len(tree.Children) > 2 {
+		var err exception.Exception
+		parent, err = interpreter.resolveName(tree.Children[2])
+		if err != nil {
+			return nil, err
+		}
+	}
+	classType, err := interpreter.newClass(tree, parent)
+	if err != nil {
+		return nil, err
+	}
+	err = interpreter.declareVariable(interpreter.resolution.variables[tree.Children[0]], classType, false, tree.Line, tree.Col)
+	if err != nil {
+		return nil, err
+	}
+	return classType, nil
+}
+
+// Creates the type a class statement defines, extending parent if it
+// isn't nil
+func (interpreter *Interpreter) newClass(tree *parser.Token, parent *OtterValue) (*OtterValue, exception.Exception) {
+	name := tree.Children[0].Value
+	classType := &OtterValue{
+		Type:    interpreter.MustResolveType(TType),
+		Value:   TypeName(name),
+		Methods: map[string]*Callable{},
+	}
+	if parent != nil {
+		parentTree := tree.Children[2]
+		if !parent.IsInstanceOf(TType) {
+			return nil, exception.New(exception.TypeError, fmt.Sprintf("%v cannot extend %v, which is not a type", name, parentTree.Value), parentTree.Line, parentTree.Col)
+		}
+		classType.Parent = parent
+	}
+	for _, definition := range tree.Children[1].Children {
+		method, err := interpreter.NewUserDefinedFunction(definition)
+		if err != nil {
+			return nil, err
+		}
+		method.Callable.Method = true
+		method.Callable.Arity++
+		method.Callable.Class = classType
+		classType.Methods[method.Callable.Name] = method.Callable
+	}
+	classType.Callable = &Callable{
+		Name:  name,
+		Class: classType,
+		BuiltInFunction: func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+			return interpreter.newInstance(classType, values)
+		},
+	}
+	// Classes without an init of their own use their parent's
+	if init, found := findMethod(classType, InitMethodName); found {
+		classType.Callable.Arity = len(init.Parameters)
+	} else if base := builtinBase(classType); base != nil {
+		classType.Callable.Arity = base.Callable.Arity
+	}
+	return classType, nil
+}
+
+func (interpreter *Interpreter) newInstance(classType *OtterValue, arguments []*OtterValue) (*OtterValue, exception.Exception) {
+	instance := &OtterValue{
+		Type:   classType,
+		Fields: map[string]*OtterValue{},
+	}
+	var err exception.Exception
+	if _, found := findMethod(classType, InitMethodName); found {
+		_, err = interpreter.callMethod(instance, InitMethodName, arguments, 0, 0)
+	} else {
+		_, err = interpreter.superInit(classType, instance, arguments, 0, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if builtinBase(classType) != nil && instance.Value == nil {
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("%v.init must call super.init", classType.Value), 0, 0)
+	}
+	return instance, nil
+}
+
+// Finds a method on a type or the nearest type it extends that has it
+func findMethod(t *OtterValue, methodName string) (*Callable, bool) {
+	for ; t != nil; t = t.Parent {
+		if method, found := t.Methods[methodName]; found {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+func isClass(t *OtterValue) bool {
+	return t.Callable != nil && t.Callable.Class == t
+}
+
+// Returns the builtin type a class ultimately extends, or nil if it
+// doesn't extend one
+func builtinBase(classType *OtterValue) *OtterValue {
+	t := classType
+	for t.Parent != nil {
+		t = t.Parent
+	}
+	if isClass(t) {
+		return nil
+	}
+	return t
+}
+
+// Evaluates super.method(...), calling the method the parent of the
+// current method's class would use, with this as the instance
+func (interpreter *Interpreter) doSuperAccess(tree *parser.Token) (*OtterValue, exception.Exception) {
+	this, class, err := interpreter.resolveSuper(tree)
+	if err != nil {
+		return nil, err
+	}
+	targetTree := tree.Children[1]
+	arguments := []*OtterValue{}
+	if targetTree.Symbol == parser.FunctionInvocation {
+		arguments, err = interpreter.evaluateArguments(targetTree.Children[1:])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return interpreter.callSuper(this, class, tree, arguments)
+}
+
+// Finds the instance and class of the method a super access is in
+func (interpreter *Interpreter) resolveSuper(tree *parser.Token) (*OtterValue, *OtterValue, exception.Exception) {
+	superTree := tree.Children[0]
+	this, err := interpreter.resolveName(superTree)
+	if err != nil {
+		return nil, nil, err
+	}
+	class, err := interpreter.loadVariable(interpreter.resolution.variables[tree], superTree.Line, superTree.Col)
+	if err != nil {
+		return nil, nil, err
+	}
+	return this, class, nil
+}
+
+// Calls the method named by the super access tree on the parent of
+// class, with this as the instance
+func (interpreter *Interpreter) callSuper(this *OtterValue, class *OtterValue, tree *parser.Token, arguments []*OtterValue) (*OtterValue, exception.Exception) {
+	superTree := tree.Children[0]
+	targetTree := tree.Children[1]
+	methodName := targetTree.Value
+	if targetTree.Symbol == parser.FunctionInvocation {
+		methodName = targetTree.Children[0].Value
+	}
+	if method, found := findMethod(class.Parent, methodName); found {
+		fullArguments := append([]*OtterValue{this}, arguments...)
+		return interpreter.invokeCallable(method, fullArguments, targetTree.Line, targetTree.Col)
+	}
+	if methodName == InitMethodName {
+		return interpreter.superInit(class, this, arguments, targetTree.Line, targetTree.Col)
+	}
+	if class.Parent == nil {
+		return nil, exception.New(exception.MethodError, fmt.Sprintf("%v has no parent type", class.Value), superTree.Line, superTree.Col)
+	}
+	return nil, exception.New(exception.MethodError, fmt.Sprintf("%v has no method %v", class.Parent.Value, methodName), targetTree.Line, targetTree.Col)
+}
+
+// Runs the init a class inherits when no class in its hierarchy
+// defines one. Classes that extend a builtin type construct a value
+// of that type from the arguments. Other classes take no arguments
+func (interpreter *Interpreter) superInit(class *OtterValue, this *OtterValue, arguments []*OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	base := builtinBase(class)
+	if base == nil {
+		if len(arguments) != 0 {
+			return nil, exception.New(exception.TypeError, fmt.Sprintf("%v takes exactly 0 arguments, found %v", InitMethodName, len(arguments)), line, col)
+		}
+		return interpreter.NewNull(), nil
+	}
+	value, err := interpreter.invokeCallable(base.Callable, arguments, line, col)
+	if err != nil {
+		return nil, err
+	}
+	this.Value = value.Value
+	return interpreter.NewNull(), nil
+}
+
+// Evaluates value instanceof Type, which is true if the value's type
+// is Type or extends it
+func (interpreter *Interpreter) doInstanceOf(tree *parser.Token) (*OtterValue, exception.Exception) {
+	value, typeValue, err := resolveBinaryOperands(interpreter, tree)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.instanceOf(value, typeValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) instanceOf(value *OtterValue, typeValue *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	if !typeValue.IsInstanceOf(TType) {
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("right side of instanceof must be a type, got %v", typeValue.Type.Value), line, col)
+	}
+	return interpreter.NewBool(value.Type.isSubtypeOf(typeValue)), nil
+}
+
+// Evaluates target.field = value. Only instances of user defined
+// classes have fields
+func (interpreter *Interpreter) doFieldAssignment(accessTree *parser.Token, valueTree *parser.Token) (*OtterValue, exception.Exception) {
+	target, err := interpreter.Evaluate(accessTree.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	field := accessTree.Children[1]
+	if err := checkHasFields(target, field); err != nil {
+		return nil, err
+	}
+	value, err := interpreter.Evaluate(valueTree)
+	if err != nil {
+		return nil, err
+	}
+	target.Fields[field.Value] = value
+	return value, nil
+}
+
+func checkHasFields(target *OtterValue, field *parser.Token) exception.Exception {
+	if module, ok := target.Value.(*Module); ok {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot assign to %v.%v outside module %v", module.Name, field.Value, module.Name), field.Line, field.Col)
+	}
+	if target.Fields == nil {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot set field %v on %v", field.Value, target.Type.Value), field.Line, field.Col)
+	}
+	return nil
+}
+
+// Shows an instance as its class name followed by its fields in
+// alphabetical order, like Point{x: 1, y: 2}
+func instanceString(value *OtterValue, inProgress map[*OtterValue]bool) string {
+	if inProgress[value] {
+		return fmt.Sprintf("%v{...}", value.Type.Value)
+	}
+	inProgress[value] = true
+	defer delete(inProgress, value)
+	names := []string{}
+	for name := range value.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := []string{}
+	for _, name := range names {
+		fields = append(fields, name+": "+elementString(value.Fields[name], inProgress))
+	}
+	return fmt.Sprintf("%v{%v}", value.Type.Value, strings.Join(fields, ", "))
+}
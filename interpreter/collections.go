@@ -0,0 +1,160 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Shared support for the Otter types backed by the persistent
+// collections package: Vector, Set, PersistentMap and Range
+
+// Values are hashed so they can be stored in hashed collections.
+// Values that are equal with == hash the same
+func (value *OtterValue) Hash() uint32 {
+	return hashValue(value, hashDepth)
+}
+
+// Compares values as == does. Hashed collections can't return the
+// exceptions equals methods raise, so Equals panics with them, and
+// the builtin function that used the collection raises them
+func (value *OtterValue) Equals(other interface{}) bool {
+	otherValue, ok := other.(*OtterValue)
+	if !ok {
+		return false
+	}
+	equal, err := newEqualityChecker(nil).equal(value, otherValue)
+	if err != nil {
+		panic(keyException{err})
+	}
+	return equal
+}
+
+// An exception raised by an equals method that a hashed collection
+// called
+type keyException struct {
+	err exception.Exception
+}
+
+// Recovers from a panic caused by a keyException, storing the
+// exception in err. Any other panic continues
+func recoverKeyException(err *exception.Exception) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	keyErr, ok := recovered.(keyException)
+	if !ok {
+		panic(recovered)
+	}
+	*err = keyErr.err
+}
+
+// Adapts an Otter function to the callbacks taken by the collections
+// package. Those callbacks can't fail, so the first exception raised
+// is recorded in err and the function is not called again
+type callbackAdapter struct {
+	interpreter *Interpreter
+	function    *OtterValue
+	err         exception.Exception
+}
+
+func (interpreter *Interpreter) newCallbackAdapter(function *OtterValue, methodName string) (*callbackAdapter, exception.Exception) {
+	if function.Callable == nil {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v expects a function, got %v", methodName, function.Type.Value), 0, 0)
+	}
+	return &callbackAdapter{
+		interpreter: interpreter,
+		function:    function,
+	}, nil
+}
+
+func (adapter *callbackAdapter) call(arguments ...*OtterValue) *OtterValue {
+	if adapter.err != nil {
+		return adapter.interpreter.NewNull()
+	}
+	result, err := adapter.interpreter.invokeCallable(adapter.function.Callable, arguments, 0, 0)
+	if err != nil {
+		adapter.err = err
+		return adapter.interpreter.NewNull()
+	}
+	return result
+}
+
+func (adapter *callbackAdapter) mapFn(value interface{}) interface{} {
+	return adapter.call(value.(*OtterValue))
+}
+
+func (adapter *callbackAdapter) filterFn(value interface{}) bool {
+	return adapter.interpreter.Truthiness(adapter.call(value.(*OtterValue))).Value == true
+}
+
+func (adapter *callbackAdapter) reducerFn(accumulator interface{}, value interface{}) interface{} {
+	return adapter.call(accumulator.(*OtterValue), value.(*OtterValue))
+}
+
+func toOtterValues(values []interface{}) []*OtterValue {
+	otterValues := make([]*OtterValue, len(values))
+	for i, value := range values {
+		otterValues[i] = value.(*OtterValue)
+	}
+	return otterValues
+}
+
+type CollectionIteratorInternals struct {
+	Iterator collections.Iterator
+	// Whether the underlying iterator has been moved on to the
+	// next element, and if so whether there was one
+	advanced bool
+	hasNext  bool
+}
+
+// Adapts an iterator from the collections package to the
+// hasNext/getNext protocol used by for loops
+func (interpreter *Interpreter) NewCollectionIterator(iterator collections.Iterator) *OtterValue {
+	return &OtterValue{
+		Type: interpreter.MustResolveType(TCollectionIterator),
+		Value: &CollectionIteratorInternals{
+			Iterator: iterator,
+		},
+	}
+}
+
+func (internals *CollectionIteratorInternals) advance() {
+	if !internals.advanced {
+		internals.hasNext = internals.Iterator.MoveNext()
+		internals.advanced = true
+	}
+}
+
+func CollectionIteratorHasNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*CollectionIteratorInternals)
+	internals.advance()
+	return interpreter.NewBool(internals.hasNext), nil
+}
+
+func CollectionIteratorGetNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*CollectionIteratorInternals)
+	internals.advance()
+	if !internals.hasNext {
+		return nil, exception.New(exception.IterationError, "iterable has no more elements", 0, 0)
+	}
+	internals.advanced = false
+	return internals.Iterator.Current().(*OtterValue), nil
+}
+
+func ConstructCollectionIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return nil, exception.New(exception.TypeError, "CollectionIterators are created by calling iterator on a collection", 0, 0)
+}
+
+func DefineCollectionTypes(interpreter *Interpreter) {
+	interpreter.DefineType(TCollectionIterator, NewBuiltInConstructor(TCollectionIterator, 0, ConstructCollectionIterator))
+	interpreter.DefineBuiltinMethod(TCollectionIterator, "hasNext", 1, CollectionIteratorHasNext)
+	interpreter.DefineBuiltinMethod(TCollectionIterator, "getNext", 1, CollectionIteratorGetNext)
+
+	DefineVectorType(interpreter)
+	DefineSetType(interpreter)
+	DefinePersistentMapType(interpreter)
+	DefineRangeType(interpreter)
+}
@@ -0,0 +1,685 @@
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// The compiler turns unsweetened syntax trees into bytecode chunks.
+// Every tree compiles to code that leaves exactly one value on the
+// stack, its value in the tree walking interpreter. The compiler
+// tracks how deep the stack is, and what statements like break and
+// return need to undo when they jump out of the code enclosing them
+
+type unwindKind int
+
+const (
+	unwindScope unwindKind = iota
+	unwindTry
+	unwindCatch
+	unwindFinally
+	unwindLoop
+)
+
+// Something a jump out of the code being compiled must undo, like a
+// block scope, or do, like a finally block
+type unwindEntry struct {
+	kind unwindKind
+	// The finally block to run
+	finally *parser.Token
+	// For loops, their label, the stack depth holding their result,
+	// where continue jumps to and the jumps that break out of them
+	label          string
+	depth          int
+	continueTarget int
+	breakJumps     []int
+}
+
+type compiler struct {
+	interpreter *Interpreter
+	chunk       *Chunk
+	names       map[string]int
+	depth       int
+	unwinding   []*unwindEntry
+}
+
+// Compiles statements to a chunk. The chunk's value is the value of
+// the last statement
+func (interpreter *Interpreter) compile(name string, statements []*parser.Token, isFunction bool) (*Chunk, exception.Exception) {
+	c := &compiler{
+		interpreter: interpreter,
+		chunk:       &Chunk{Name: name, IsFunction: isFunction},
+		names:       map[string]int{},
+	}
+	if err := c.compileStatements(statements); err != nil {
+		return nil, err
+	}
+	return c.chunk, nil
+}
+
+// Returns the compiled body of a function definition or literal,
+// compiling it the first time it is needed
+func (interpreter *Interpreter) functionChunk(tree *parser.Token) (*Chunk, exception.Exception) {
+	if chunk, found := interpreter.chunks[tree]; found {
+		return chunk, nil
+	}
+	if err := ValidateFunctionDefinition(tree); err != nil {
+		return nil, err
+	}
+	body := tree.Children[len(tree.Children)-1]
+	chunk, err := interpreter.compile(functionChunkName(tree), body.Children, true)
+	if err != nil {
+		return nil, err
+	}
+	interpreter.chunks[tree] = chunk
+	return chunk, nil
+}
+
+func (c *compiler) emit(op Opcode, a int, b int, line int, col int) int {
+	c.chunk.Instructions = append(c.chunk.Instructions, Instruction{Op: op, A: a, B: b})
+	c.chunk.Positions = append(c.chunk.Positions, Position{Line: line, Col: col})
+	c.depth += stackEffect(op, a, b)
+	return len(c.chunk.Instructions) - 1
+}
+
+// How an instruction changes the depth of the stack
+func stackEffect(op Opcode, a int, b int) int {
+	switch op {
+	case OpConstant, OpNull, OpLoadVariable, OpFunction, OpImport, OpImportName:
+		return 1
+	case OpClass:
+		return 1 - b
+	case OpPop, OpAdd, OpSubtract, OpMultiply, OpDivide, OpModulo,
+		OpEqual, OpNotEqual, OpLessThan, OpGreaterThan, OpLessThanOrEqual, OpGreaterThanOrEqual,
+		OpAnd, OpOr, OpInstanceOf, OpJumpIfFalse, OpSetResult, OpGetItem, OpSetField,
+		OpReturn, OpThrow, OpEnterCatch, OpReraise:
+		return -1
+	case OpDrop:
+		return -a
+	case OpCall:
+		return -a
+	case OpCallMember:
+		return -b
+	case OpSuper:
+		return 1 - b
+	case OpSetItem:
+		return -2
+	case OpArray:
+		return 1 - a
+	case OpMap:
+		return 1 - 2*a
+	}
+	return 0
+}
+
+// Points a jump emitted earlier at the next instruction
+func (c *compiler) patchJump(jump int) {
+	c.patchJumpTo(jump, len(c.chunk.Instructions))
+}
+
+func (c *compiler) patchJumpTo(jump int, target int) {
+	instruction := &c.chunk.Instructions[jump]
+	if instruction.Op == OpMatchCatch {
+		instruction.B = target
+	} else {
+		instruction.A = target
+	}
+}
+
+func (c *compiler) constant(value *OtterValue) int {
+	c.chunk.Constants = append(c.chunk.Constants, value)
+	return len(c.chunk.Constants) - 1
+}
+
+func (c *compiler) name(name string) int {
+	if index, found := c.names[name]; found {
+		return index
+	}
+	c.chunk.Names = append(c.chunk.Names, name)
+	c.names[name] = len(c.chunk.Names) - 1
+	return c.names[name]
+}
+
+// Returns the index of the variable the resolver found a name refers to
+func (c *compiler) variable(name *parser.Token) int {
+	c.chunk.Variables = append(c.chunk.Variables, c.interpreter.resolution.variables[name])
+	return len(c.chunk.Variables) - 1
+}
+
+func (c *compiler) layout(tree *parser.Token) int {
+	c.chunk.Layouts = append(c.chunk.Layouts, c.interpreter.resolution.layouts[tree])
+	return len(c.chunk.Layouts) - 1
+}
+
+func (c *compiler) token(tree *parser.Token) int {
+	c.chunk.Tokens = append(c.chunk.Tokens, tree)
+	return len(c.chunk.Tokens) - 1
+}
+
+func (c *compiler) pushUnwind(entry *unwindEntry) {
+	c.unwinding = append(c.unwinding, entry)
+}
+
+func (c *compiler) popUnwind() {
+	c.unwinding = c.unwinding[:len(c.unwinding)-1]
+}
+
+// Compiles a sequence of statements, keeping only the value of the
+// last one. An empty sequence has the value null
+func (c *compiler) compileStatements(statements []*parser.Token) exception.Exception {
+	if len(statements) == 0 {
+		c.emit(OpNull, 0, 0, 0, 0)
+		return nil
+	}
+	for i, statement := range statements {
+		if i > 0 {
+			c.emit(OpPop, 0, 0, statement.Line, statement.Col)
+		}
+		if err := c.compileNode(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var binaryOpcodes = map[parser.Symbol]Opcode{
+	"&&":         OpAnd,
+	"||":         OpOr,
+	"==":         OpEqual,
+	"!=":         OpNotEqual,
+	"+":          OpAdd,
+	"-":          OpSubtract,
+	"*":          OpMultiply,
+	"/":          OpDivide,
+	"%":          OpModulo,
+	"<":          OpLessThan,
+	">":          OpGreaterThan,
+	"<=":         OpLessThanOrEqual,
+	">=":         OpGreaterThanOrEqual,
+	"instanceof": OpInstanceOf,
+}
+
+func (c *compiler) compileNode(tree *parser.Token) exception.Exception {
+	line, col := tree.Line, tree.Col
+	if op, found := binaryOpcodes[tree.Symbol]; found {
+		if tree.Symbol == "-" && len(tree.Children) == 1 {
+			if err := c.compileNode(tree.Children[0]); err != nil {
+				return err
+			}
+			c.emit(OpNegate, 0, 0, line, col)
+			return nil
+		}
+		if len(tree.Children) != 2 {
+			return exception.New(exception.SyntaxError, fmt.Sprintf("invalid symbol %v", tree.Value), line, col)
+		}
+		if err := c.compileNodes(tree.Children); err != nil {
+			return err
+		}
+		c.emit(op, 0, 0, line, col)
+		return nil
+	}
+	switch tree.Symbol {
+	case parser.StringLiteral:
+		c.emit(OpConstant, c.constant(c.interpreter.NewString(tree.Value)), 0, line, col)
+	case parser.IntLiteral:
+		parsedInt, err := strconv.ParseInt(tree.Value, 0, 64)
+		if err != nil {
+			return exception.Wrap(exception.SyntaxError, fmt.Sprintf("invalid int literal %v", tree.Value), err, line, col)
+		}
+		c.emit(OpConstant, c.constant(c.interpreter.NewInt(parsedInt)), 0, line, col)
+	case parser.FloatLiteral:
+		parsedFloat, err := strconv.ParseFloat(tree.Value, 64)
+		if err != nil {
+			return exception.Wrap(exception.SyntaxError, fmt.Sprintf("invalid float literal %v", tree.Value), err, line, col)
+		}
+		c.emit(OpConstant, c.constant(c.interpreter.NewFloat(parsedFloat)), 0, line, col)
+	case "true":
+		c.emit(OpConstant, c.constant(c.interpreter.True()), 0, line, col)
+	case "false":
+		c.emit(OpConstant, c.constant(c.interpreter.False()), 0, line, col)
+	case parser.Name:
+		c.emit(OpLoadVariable, c.variable(tree), 0, line, col)
+	case parser.Assignment:
+		return c.compileAssignment(tree)
+	case parser.While:
+		return c.compileWhile(tree)
+	case parser.Break, parser.Continue:
+		return c.compileLoopControl(tree)
+	case parser.FunctionDefinition, parser.FunctionLiteral:
+		chunk, err := c.interpreter.functionChunk(tree)
+		if err != nil {
+			return err
+		}
+		c.chunk.Functions = append(c.chunk.Functions, chunk)
+		c.emit(OpFunction, c.token(tree), 0, line, col)
+		if tree.Symbol == parser.FunctionDefinition {
+			c.emit(OpDeclareVariable, c.variable(tree.Children[0]), 0, line, col)
+		}
+	case parser.FunctionInvocation:
+		if err := c.compileNodes(tree.Children); err != nil {
+			return err
+		}
+		c.emit(OpCall, len(tree.Children)-1, c.name(calleeName(tree.Children[0])), line, col)
+	case parser.Block:
+		// Blocks that declare nothing don't need a scope of their own
+		if len(c.interpreter.resolution.layouts[tree].names) == 0 {
+			return c.compileStatements(tree.Children)
+		}
+		c.emit(OpPushScope, c.layout(tree), 0, line, col)
+		c.pushUnwind(&unwindEntry{kind: unwindScope})
+		if err := c.compileStatements(tree.Children); err != nil {
+			return err
+		}
+		c.popUnwind()
+		c.emit(OpPopScope, 0, 0, line, col)
+	case parser.Let, parser.Const:
+		return c.compileDeclaration(tree)
+	case "return":
+		return c.compileReturn(tree)
+	case "if":
+		return c.compileIf(tree)
+	case parser.Access:
+		return c.compileAccess(tree)
+	case parser.Index:
+		if err := c.compileNodes(tree.Children[:2]); err != nil {
+			return err
+		}
+		c.emit(OpGetItem, 0, 0, line, col)
+	case parser.ArrayLiteral:
+		if err := c.compileNodes(tree.Children); err != nil {
+			return err
+		}
+		c.emit(OpArray, len(tree.Children), 0, line, col)
+	case parser.MapLiteral:
+		pairs := len(tree.Children) / 2
+		if err := c.compileNodes(tree.Children[:2*pairs]); err != nil {
+			return err
+		}
+		c.emit(OpMap, pairs, 0, line, col)
+	case parser.Class:
+		for _, definition := range tree.Children[1].Children {
+			chunk, err := c.interpreter.functionChunk(definition)
+			if err != nil {
+				return err
+			}
+			c.chunk.Functions = append(c.chunk.Functions, chunk)
+		}
+		hasParent := 0
+		if len(tree.Children) > 2 {
+			if err := c.compileNode(tree.Children[2]); err != nil {
+				return err
+			}
+			hasParent = 1
+		}
+		c.emit(OpClass, c.token(tree), hasParent, line, col)
+		c.emit(OpDeclareVariable, c.variable(tree.Children[0]), 0, line, col)
+	case parser.Import:
+		name := tree.Children[1]
+		c.emit(OpImport, c.name(tree.Children[0].Value), 0, line, col)
+		c.emit(OpDeclareVariable, c.variable(name), 0, name.Line, name.Col)
+	case parser.FromImport:
+		// The statement's value is the module
+		c.emit(OpImport, c.name(tree.Children[0].Value), 0, line, col)
+		for _, name := range tree.Children[1:] {
+			c.emit(OpImportName, c.name(name.Value), 0, name.Line, name.Col)
+			c.emit(OpDeclareVariable, c.variable(name), 0, name.Line, name.Col)
+			c.emit(OpPop, 0, 0, name.Line, name.Col)
+		}
+	case parser.Try:
+		return c.compileTry(tree)
+	case parser.Throw:
+		if len(tree.Children) == 0 {
+			c.emit(OpRethrow, 0, 0, line, col)
+			// Nothing follows a rethrow, but the statement still
+			// has a value as far as the compiler is concerned
+			c.depth++
+			return nil
+		}
+		if err := c.compileNode(tree.Children[0]); err != nil {
+			return err
+		}
+		c.emit(OpThrow, 0, 0, line, col)
+		c.depth++
+	default:
+		return exception.New(exception.SyntaxError, fmt.Sprintf("unrecognized symbol '%v'", tree.Value), line, col)
+	}
+	return nil
+}
+
+func (c *compiler) compileNodes(trees []*parser.Token) exception.Exception {
+	for _, tree := range trees {
+		if err := c.compileNode(tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) compileAssignment(tree *parser.Token) exception.Exception {
+	if len(tree.Children) != 2 {
+		return exception.New(exception.SyntaxError, "invalid assignment expression", tree.Line, tree.Col)
+	}
+	left := tree.Children[0]
+	right := tree.Children[1]
+	switch {
+	case left.Symbol == parser.Index:
+		if err := c.compileNodes([]*parser.Token{left.Children[0], left.Children[1], right}); err != nil {
+			return err
+		}
+		c.emit(OpSetItem, 0, 0, left.Line, left.Col)
+	case left.Symbol == parser.Access && left.Children[1].Symbol == parser.Name:
+		field := left.Children[1]
+		if err := c.compileNode(left.Children[0]); err != nil {
+			return err
+		}
+		c.emit(OpCheckFields, c.token(field), 0, field.Line, field.Col)
+		if err := c.compileNode(right); err != nil {
+			return err
+		}
+		c.emit(OpSetField, c.name(field.Value), 0, field.Line, field.Col)
+	case left.Symbol == parser.Name:
+		if err := c.compileNode(right); err != nil {
+			return err
+		}
+		c.emit(OpStoreVariable, c.variable(left), 0, tree.Line, tree.Col)
+	default:
+		return exception.New(exception.SyntaxError, "invalid assignment expression", tree.Line, tree.Col)
+	}
+	return nil
+}
+
+func (c *compiler) compileDeclaration(tree *parser.Token) exception.Exception {
+	if len(tree.Children) == 0 {
+		return exception.New(exception.SyntaxError, "invalid declaration", tree.Line, tree.Col)
+	}
+	name := tree.Children[0]
+	if len(tree.Children) > 1 {
+		if err := c.compileNode(tree.Children[1]); err != nil {
+			return err
+		}
+	} else {
+		c.emit(OpNull, 0, 0, tree.Line, tree.Col)
+	}
+	op := OpDeclareVariable
+	if tree.Symbol == parser.Const {
+		op = OpDeclareConstant
+	}
+	c.emit(op, c.variable(name), 0, name.Line, name.Col)
+	return nil
+}
+
+// An if's value is the value of the block that runs, or null if
+// none does
+func (c *compiler) compileIf(tree *parser.Token) exception.Exception {
+	if err := c.compileNode(tree.Children[0]); err != nil {
+		return err
+	}
+	skipThen := c.emit(OpJumpIfFalse, 0, 0, tree.Line, tree.Col)
+	if err := c.compileNode(tree.Children[1]); err != nil {
+		return err
+	}
+	skipElse := c.emit(OpJump, 0, 0, tree.Line, tree.Col)
+	c.patchJump(skipThen)
+	c.depth--
+	if len(tree.Children) > 2 {
+		elseTree := tree.Children[2]
+		var err exception.Exception
+		if elseTree.Symbol == parser.ElseIf {
+			err = c.compileIf(elseTree)
+		} else {
+			err = c.compileNode(elseTree)
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		c.emit(OpNull, 0, 0, tree.Line, tree.Col)
+	}
+	c.patchJump(skipElse)
+	return nil
+}
+
+// A while loop keeps its result, the value of the last iteration
+// that ran to the end of the block, below the values it works with
+func (c *compiler) compileWhile(tree *parser.Token) exception.Exception {
+	if len(tree.Children) != 2 && len(tree.Children) != 3 {
+		return exception.New(exception.SyntaxError, "invalid while block", tree.Line, tree.Col)
+	}
+	c.emit(OpNull, 0, 0, tree.Line, tree.Col)
+	loop := &unwindEntry{
+		kind:           unwindLoop,
+		depth:          c.depth,
+		continueTarget: len(c.chunk.Instructions),
+	}
+	if len(tree.Children) == 3 {
+		loop.label = tree.Children[2].Value
+	}
+	if err := c.compileNode(tree.Children[0]); err != nil {
+		return err
+	}
+	exit := c.emit(OpJumpIfFalse, 0, 0, tree.Line, tree.Col)
+	c.pushUnwind(loop)
+	if err := c.compileNode(tree.Children[1]); err != nil {
+		return err
+	}
+	c.popUnwind()
+	c.emit(OpSetResult, 0, 0, tree.Line, tree.Col)
+	c.emit(OpJump, loop.continueTarget, 0, tree.Line, tree.Col)
+	c.patchJump(exit)
+	for _, jump := range loop.breakJumps {
+		c.patchJump(jump)
+	}
+	return nil
+}
+
+func (c *compiler) compileLoopControl(tree *parser.Token) exception.Exception {
+	label := ""
+	if len(tree.Children) > 0 {
+		label = tree.Children[0].Value
+	}
+	index := len(c.unwinding) - 1
+	for ; index >= 0; index-- {
+		entry := c.unwinding[index]
+		if entry.kind == unwindLoop && (label == "" || entry.label == label) {
+			break
+		}
+	}
+	if index < 0 {
+		return exception.New(exception.SyntaxError, fmt.Sprintf("%v outside of a loop", tree.Value), tree.Line, tree.Col)
+	}
+	loop := c.unwinding[index]
+	depth := c.depth
+	if err := c.unwindTo(index+1, tree); err != nil {
+		return err
+	}
+	if c.depth > loop.depth {
+		c.emit(OpDrop, c.depth-loop.depth, 0, tree.Line, tree.Col)
+	}
+	if tree.Symbol == parser.Break {
+		loop.breakJumps = append(loop.breakJumps, c.emit(OpJump, 0, 0, tree.Line, tree.Col))
+	} else {
+		c.emit(OpJump, loop.continueTarget, 0, tree.Line, tree.Col)
+	}
+	// Nothing follows the jump, but the statement still has a value
+	// as far as the compiler is concerned
+	c.depth = depth + 1
+	return nil
+}
+
+func (c *compiler) compileReturn(tree *parser.Token) exception.Exception {
+	if len(tree.Children) > 0 {
+		if err := c.compileNode(tree.Children[0]); err != nil {
+			return err
+		}
+	} else {
+		c.emit(OpNull, 0, 0, tree.Line, tree.Col)
+	}
+	if c.chunk.IsFunction {
+		if err := c.unwindTo(0, tree); err != nil {
+			return err
+		}
+	}
+	c.emit(OpReturn, 0, 0, tree.Line, tree.Col)
+	c.depth++
+	return nil
+}
+
+// Emits the code that undoes the unwind entries above index, innermost
+// first, before a jump out of them
+func (c *compiler) unwindTo(index int, tree *parser.Token) exception.Exception {
+	for i := len(c.unwinding) - 1; i >= index; i-- {
+		entry := c.unwinding[i]
+		switch entry.kind {
+		case unwindScope:
+			c.emit(OpPopScope, 0, 0, tree.Line, tree.Col)
+		case unwindTry:
+			c.emit(OpPopTry, 0, 0, tree.Line, tree.Col)
+		case unwindCatch:
+			c.emit(OpLeaveCatch, 0, 0, tree.Line, tree.Col)
+		case unwindFinally:
+			if err := c.compileFinally(entry, i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Compiles a copy of a finally block where it runs. Jumps out of the
+// finally block don't run it again, so only the unwind entries below
+// it apply
+func (c *compiler) compileFinally(entry *unwindEntry, index int) exception.Exception {
+	unwinding := c.unwinding
+	// A copy, so the entries the finally block pushes don't
+	// overwrite the ones above it
+	c.unwinding = append([]*unwindEntry{}, c.unwinding[:index]...)
+	defer func() { c.unwinding = unwinding }()
+	if err := c.compileNode(entry.finally); err != nil {
+		return err
+	}
+	c.emit(OpPop, 0, 0, entry.finally.Line, entry.finally.Col)
+	return nil
+}
+
+func (c *compiler) compileAccess(tree *parser.Token) exception.Exception {
+	valueTree := tree.Children[0]
+	targetTree := tree.Children[1]
+	arguments := []*parser.Token{}
+	if targetTree.Symbol == parser.FunctionInvocation {
+		arguments = targetTree.Children[1:]
+	}
+	if valueTree.Symbol == parser.Name && valueTree.Value == SuperName {
+		if err := c.compileNodes(arguments); err != nil {
+			return err
+		}
+		c.emit(OpSuper, c.token(tree), len(arguments), targetTree.Line, targetTree.Col)
+		return nil
+	}
+	if err := c.compileNode(valueTree); err != nil {
+		return err
+	}
+	if targetTree.Symbol == parser.FunctionInvocation {
+		if err := c.compileNodes(arguments); err != nil {
+			return err
+		}
+		c.emit(OpCallMember, c.name(targetTree.Children[0].Value), len(arguments), targetTree.Line, targetTree.Col)
+		return nil
+	}
+	c.emit(OpGetMember, c.name(targetTree.Value), 0, targetTree.Line, targetTree.Col)
+	return nil
+}
+
+// A try statement's value is the value of its block, or of the catch
+// block that handled an exception. Exceptions jump to a handler that
+// tries each catch clause in turn, and raises the exception again if
+// none handles it. Every way out of the statement runs a copy of the
+// finally block
+func (c *compiler) compileTry(tree *parser.Token) exception.Exception {
+	if len(tree.Children) < 2 {
+		return exception.New(exception.SyntaxError, "invalid try statement", tree.Line, tree.Col)
+	}
+	var finally *unwindEntry
+	catches := []*parser.Token{}
+	for _, clause := range tree.Children[1:] {
+		if clause.Symbol == parser.Finally {
+			finally = &unwindEntry{kind: unwindFinally, finally: clause.Children[0]}
+		} else {
+			catches = append(catches, clause)
+		}
+	}
+	finallyIndex := len(c.unwinding)
+	if finally != nil {
+		c.pushUnwind(finally)
+	}
+	runFinally := func() exception.Exception {
+		if finally == nil {
+			return nil
+		}
+		return c.compileFinally(finally, finallyIndex)
+	}
+
+	depth := c.depth
+	handler := c.emit(OpSetupTry, 0, 0, tree.Line, tree.Col)
+	c.pushUnwind(&unwindEntry{kind: unwindTry})
+	if err := c.compileNode(tree.Children[0]); err != nil {
+		return err
+	}
+	c.popUnwind()
+	c.emit(OpPopTry, 0, 0, tree.Line, tree.Col)
+	done := []int{c.emit(OpJump, 0, 0, tree.Line, tree.Col)}
+
+	// The handler starts with the exception on the stack
+	c.patchJump(handler)
+	c.depth = depth + 1
+	for _, catch := range catches {
+		typeName := -1
+		if len(catch.Children) > 2 {
+			typeName = c.name(catch.Children[2].Value)
+		}
+		nextCatch := c.emit(OpMatchCatch, typeName, 0, catch.Line, catch.Col)
+		c.emit(OpEnterCatch, c.layout(catch), 0, catch.Line, catch.Col)
+		c.pushUnwind(&unwindEntry{kind: unwindCatch})
+		catchHandler := c.emit(OpSetupTry, 0, 0, catch.Line, catch.Col)
+		c.pushUnwind(&unwindEntry{kind: unwindTry})
+		if err := c.compileNode(catch.Children[1]); err != nil {
+			return err
+		}
+		c.popUnwind()
+		c.emit(OpPopTry, 0, 0, catch.Line, catch.Col)
+		c.popUnwind()
+		c.emit(OpLeaveCatch, 0, 0, catch.Line, catch.Col)
+		done = append(done, c.emit(OpJump, 0, 0, catch.Line, catch.Col))
+
+		// Exceptions raised in the catch block stop handling the
+		// exception it caught, and aren't handled by later clauses
+		c.patchJump(catchHandler)
+		c.depth = depth + 1
+		c.emit(OpLeaveCatch, 0, 0, catch.Line, catch.Col)
+		if err := runFinally(); err != nil {
+			return err
+		}
+		c.emit(OpReraise, 0, 0, catch.Line, catch.Col)
+
+		c.patchJump(nextCatch)
+		c.depth = depth + 1
+	}
+	if err := runFinally(); err != nil {
+		return err
+	}
+	c.emit(OpReraise, 0, 0, tree.Line, tree.Col)
+
+	for _, jump := range done {
+		c.patchJump(jump)
+	}
+	c.depth = depth + 1
+	if finally != nil {
+		c.popUnwind()
+		if err := runFinally(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package interpreter
+
+import (
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// Evaluates a block in a new block scope, so variables declared
+// with let and const inside it are not visible once it ends
+func (interpreter *Interpreter) doBlock(tree *parser.Token) (*OtterValue, exception.Exception) {
+	// Blocks that declare nothing don't need a scope of their own
+	if names := interpreter.resolution.layouts[tree].names; len(names) > 0 {
+		interpreter.CallStack.PushScope(names)
+		defer interpreter.CallStack.PopScope()
+	}
+	var result *OtterValue
+	var err exception.Exception
+	for _, child := range tree.Children {
+		result, err = interpreter.Evaluate(child)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (interpreter *Interpreter) doDeclaration(tree *parser.Token) (*OtterValue, exception.Exception) {
+	if len(tree.Children) == 0 {
+		return nil, exception.New(exception.SyntaxError, "invalid declaration", tree.Line, tree.Col)
+	}
+	name := tree.Children[0]
+	value := interpreter.NewNull()
+	if len(tree.Children) > 1 {
+		var err exception.Exception
+		value, err = interpreter.Evaluate(tree.Children[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := interpreter.declareVariable(interpreter.resolution.variables[name], value, tree.Symbol == parser.Const, name.Line, name.Col)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -12,15 +12,16 @@ func NewEngine() *Engine {
 	parserFactory := func(source io.Reader) parser.Parser {
 		return parser.NewParser(source)
 	}
-	return &Engine{
+	engine := &Engine{
 		ParserFactory: parserFactory,
-		Interpreter:   *interpreter,
+		Interpreter:   interpreter,
 	}
+	return engine
 }
 
 type Engine struct {
 	ParserFactory func(io.Reader) parser.Parser
-	Interpreter   Interpreter
+	Interpreter   *Interpreter
 }
 
 func (engine *Engine) Execute(source io.Reader) (*OtterValue, exception.Exception) {
@@ -31,3 +32,14 @@ func (engine *Engine) Execute(source io.Reader) (*OtterValue, exception.Exceptio
 	}
 	return engine.Interpreter.Execute(trees)
 }
+
+// Executes source read from the named file. Any exception raised
+// is annotated with the file name
+func (engine *Engine) ExecuteFile(fileName string, source io.Reader) (*OtterValue, exception.Exception) {
+	engine.Interpreter.CallStack.Globals().FileName = fileName
+	value, err := engine.Execute(source)
+	if err != nil {
+		return nil, exception.WithFileName(err, fileName)
+	}
+	return value, nil
+}
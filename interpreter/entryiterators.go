@@ -0,0 +1,69 @@
+package interpreter
+
+import (
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Loops with two variables, like for key, value in aMap, get their
+// iterator by calling entryIterator rather than iterator. Each
+// element of an entry iterator is an array of two values which are
+// unpacked into the loop variables. Maps produce their keys and
+// values, while sequences produce each index and element
+
+type IndexedIteratorInternals struct {
+	Iterator *OtterValue
+	Index    int64
+}
+
+// Wraps an iterator so that each element is paired with its index
+func (interpreter *Interpreter) NewIndexedIterator(iterator *OtterValue) *OtterValue {
+	return &OtterValue{
+		Type: interpreter.MustResolveType(TIndexedIterator),
+		Value: &IndexedIteratorInternals{
+			Iterator: iterator,
+			Index:    0,
+		},
+	}
+}
+
+func ConstructIndexedIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return interpreter.NewIndexedIterator(values[0]), nil
+}
+
+// The entryIterator method of sequences, which pairs each element
+// produced by the sequence's own iterator with its index
+func IndexedEntryIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	iterator, err := interpreter.callMethod(values[0], "iterator", []*OtterValue{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewIndexedIterator(iterator), nil
+}
+
+func IndexedIteratorHasNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*IndexedIteratorInternals)
+	return interpreter.callMethod(internals.Iterator, "hasNext", []*OtterValue{}, 0, 0)
+}
+
+func IndexedIteratorGetNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*IndexedIteratorInternals)
+	element, err := interpreter.callMethod(internals.Iterator, "getNext", []*OtterValue{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	index := interpreter.NewInt(internals.Index)
+	internals.Index = internals.Index + 1
+	return interpreter.NewArray([]*OtterValue{index, element}), nil
+}
+
+func DefineEntryIterators(interpreter *Interpreter) {
+	interpreter.DefineType(TIndexedIterator, NewBuiltInConstructor(TIndexedIterator, 1, ConstructIndexedIterator))
+	interpreter.DefineBuiltinMethod(TIndexedIterator, "hasNext", 1, IndexedIteratorHasNext)
+	interpreter.DefineBuiltinMethod(TIndexedIterator, "getNext", 1, IndexedIteratorGetNext)
+
+	for _, sequenceType := range []TypeName{TString, TArray, TVector, TRange} {
+		interpreter.DefineBuiltinMethod(sequenceType, "entryIterator", 1, IndexedEntryIterator)
+	}
+	interpreter.DefineBuiltinMethod(TMap, "entryIterator", 1, MapEntryIterator)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "entryIterator", 1, PersistentMapEntryIterator)
+}
@@ -0,0 +1,119 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// A Binding holds the value of a global variable. The resolver refers
+// to globals by their binding, so reading one doesn't look up its name.
+// A binding whose value is nil belongs to a global that the code being
+// run declares but that hasn't been assigned yet
+type Binding struct {
+	Value *OtterValue
+	// Whether the variable was declared with const
+	Constant bool
+	// Whether the variable is one of the interpreter's builtins,
+	// which can't be assigned to or declared again
+	Builtin bool
+}
+
+// An Environment is a lexical scope. Each environment holds the
+// variables defined directly in it and a reference to the environment
+// it is nested in. Functions capture the environment they are defined
+// in, so names resolve according to where a function is written rather
+// than where it is called from.
+//
+// The global environment holds its variables by name. Every function
+// call gets a local environment, as does every block that declares
+// variables with let, const, def or class. Local environments hold
+// their variables in slots, and the resolver works out which slot of
+// which enclosing environment each name refers to before code runs.
+type Environment struct {
+	Scope Scope
+	// The local variables of a function call or block. names[i] is
+	// the name of the variable in Slots[i], which is nil until the
+	// variable is assigned
+	Slots  []*OtterValue
+	names  []string
+	Parent *Environment
+}
+
+// Creates an environment that holds variables by name
+func NewEnvironment(parent *Environment) *Environment {
+	return &Environment{
+		Scope:  NewScope(),
+		Parent: parent,
+	}
+}
+
+// Creates the environment for a function call or block with the given
+// local variables
+func NewLocalEnvironment(parent *Environment, names []string) *Environment {
+	return &Environment{
+		Slots:  make([]*OtterValue, len(names)),
+		names:  names,
+		Parent: parent,
+	}
+}
+
+// Finds the binding of the innermost variable with the given name that
+// has a value. Local variables have no bindings, so aren't found
+func (env *Environment) Lookup(variableName string) (*Binding, bool) {
+	for e := env; e != nil; e = e.Parent {
+		if binding, found := e.Scope[variableName]; found && binding.Value != nil {
+			return binding, true
+		}
+	}
+	return nil, false
+}
+
+func (env *Environment) Resolve(variableName string) (*OtterValue, bool) {
+	binding, found := env.Lookup(variableName)
+	if !found {
+		return nil, false
+	}
+	return binding.Value, true
+}
+
+// Returns the binding for a name in this environment, creating an
+// unassigned one if there is none
+func (env *Environment) binding(variableName string) *Binding {
+	binding, found := env.Scope[variableName]
+	if !found {
+		binding = &Binding{}
+		env.Scope[variableName] = binding
+	}
+	return binding
+}
+
+// Defines a variable in this environment, shadowing any variable
+// of the same name in enclosing environments
+func (env *Environment) Define(variableName string, value *OtterValue) exception.Exception {
+	return env.define(variableName, value, false)
+}
+
+// Defines a variable in this environment that cannot be reassigned
+func (env *Environment) DefineConstant(variableName string, value *OtterValue) exception.Exception {
+	return env.define(variableName, value, true)
+}
+
+func (env *Environment) define(variableName string, value *OtterValue, constant bool) exception.Exception {
+	return declareBinding(env.binding(variableName), variableName, value, constant, 0, 0)
+}
+
+// Declares a global variable at the given position. A global can be
+// declared again, by another def or by later input in the REPL, but
+// builtins and constants can't
+func declareBinding(binding *Binding, variableName string, value *OtterValue, constant bool, line int, col int) exception.Exception {
+	if binding.Builtin {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot redeclare builtin %v", variableName), line, col)
+	}
+	if binding.Constant {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot redeclare constant %v", variableName), line, col)
+	}
+	binding.Value = value
+	binding.Constant = constant
+	return nil
+}
@@ -0,0 +1,423 @@
+package interpreter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Values are compared structurally. Values of different types are
+// never equal. Collections are equal if their contents are, and
+// instances of classes are equal if their fields are. Anything else,
+// like functions and iterators, is only equal to itself
+
+type valuePair struct {
+	left  *OtterValue
+	right *OtterValue
+}
+
+type equalityChecker struct {
+	// The interpreter equals methods are called with. Hashed
+	// collections have none, and call each method with the
+	// interpreter that created it
+	interpreter *Interpreter
+	// The pairs of values being compared further up the stack. Cyclic
+	// values are equal if comparing them again would only find the
+	// same pairs, so a pair that is already being compared is taken
+	// to be equal
+	comparing map[valuePair]bool
+	// The position of the comparison being made, which equals
+	// methods are called from
+	line int
+	col  int
+}
+
+func newEqualityChecker(interpreter *Interpreter) *equalityChecker {
+	return &equalityChecker{interpreter: interpreter, comparing: map[valuePair]bool{}}
+}
+
+func (checker *equalityChecker) equal(left *OtterValue, right *OtterValue) (bool, exception.Exception) {
+	if left == right {
+		return true, nil
+	}
+	if method, found := findMethod(left.Type, EqualsMethodName); found {
+		interpreter := checker.interpreter
+		if interpreter == nil {
+			interpreter = method.interpreter
+		}
+		result, err := interpreter.callMethod(left, EqualsMethodName, []*OtterValue{right}, checker.line, checker.col)
+		if err != nil {
+			return false, err
+		}
+		return interpreter.Truthiness(result).Value == true, nil
+	}
+	if left.Type != right.Type {
+		return false, nil
+	}
+	pair := valuePair{left, right}
+	if checker.comparing[pair] {
+		return true, nil
+	}
+	checker.comparing[pair] = true
+	defer delete(checker.comparing, pair)
+
+	equal, err := checker.builtinEqual(left, right)
+	if err != nil || !equal || left.Fields == nil {
+		return equal, err
+	}
+	return checker.fieldsEqual(left, right)
+}
+
+// Compares the parts of two values of the same type that come from
+// a builtin type
+func (checker *equalityChecker) builtinEqual(left *OtterValue, right *OtterValue) (bool, exception.Exception) {
+	switch left.baseType().Value {
+	case TString, TInt, TFloat, TBool, TNull, TType, TFunction:
+		return left.isEqualTo(right), nil
+	case TArray:
+		return checker.sequencesEqual(left.Value.([]*OtterValue), right.Value.([]*OtterValue))
+	case TVector:
+		return checker.sequencesEqual(vectorElements(left.Value.(collections.Vector)), vectorElements(right.Value.(collections.Vector)))
+	case TMap, TPersistentMap:
+		return checker.entriesEqual(left, right)
+	case TSet:
+		set, otherSet := left.Value.(collections.Set), right.Value.(collections.Set)
+		return set.Size() == otherSet.Size() && set.SubsetOf(otherSet), nil
+	case TRange:
+		r, otherRange := left.Value.(*collections.Range), right.Value.(*collections.Range)
+		// Ranges with the same elements are equal however they
+		// were constructed
+		return r.Size() == otherRange.Size() &&
+			(r.Size() == 0 || r.Start() == otherRange.Start()) &&
+			(r.Size() <= 1 || r.Step() == otherRange.Step()), nil
+	}
+	// Instances of classes have no builtin part
+	return left.Fields != nil, nil
+}
+
+func (checker *equalityChecker) sequencesEqual(left []*OtterValue, right []*OtterValue) (bool, exception.Exception) {
+	if len(left) != len(right) {
+		return false, nil
+	}
+	for i := range left {
+		equal, err := checker.equal(left[i], right[i])
+		if err != nil || !equal {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (checker *equalityChecker) entriesEqual(left *OtterValue, right *OtterValue) (bool, exception.Exception) {
+	entries := mapEntries(left)
+	if len(entries) != mapSize(right) {
+		return false, nil
+	}
+	for _, entry := range entries {
+		otherValue, found := mapLookup(right, entry.Key.(*OtterValue))
+		if !found {
+			return false, nil
+		}
+		equal, err := checker.equal(entry.Value.(*OtterValue), otherValue)
+		if err != nil || !equal {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (checker *equalityChecker) fieldsEqual(left *OtterValue, right *OtterValue) (bool, exception.Exception) {
+	if len(left.Fields) != len(right.Fields) {
+		return false, nil
+	}
+	for name, value := range left.Fields {
+		otherValue, found := right.Fields[name]
+		if !found {
+			return false, nil
+		}
+		equal, err := checker.equal(value, otherValue)
+		if err != nil || !equal {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Returns the entries of a Map or PersistentMap
+func mapEntries(value *OtterValue) []collections.MapEntry {
+	if value.IsInstanceOf(TMap) {
+		order := value.Value.(*MapInternals).order
+		entries := make([]collections.MapEntry, len(order))
+		for i, entry := range order {
+			entries[i] = collections.MapEntry{Key: entry.Key, Value: entry.Value}
+		}
+		return entries
+	}
+	entries := []collections.MapEntry{}
+	value.Value.(collections.Map).ForEach(func(item interface{}) {
+		entries = append(entries, item.(collections.MapEntry))
+	})
+	return entries
+}
+
+func mapSize(value *OtterValue) int {
+	if value.IsInstanceOf(TMap) {
+		return len(value.Value.(*MapInternals).order)
+	}
+	return value.Value.(collections.Map).(collections.FiniteIterable).Size()
+}
+
+// Finds the value for a key in a Map or PersistentMap
+func mapLookup(value *OtterValue, key *OtterValue) (*OtterValue, bool) {
+	if value.IsInstanceOf(TMap) {
+		return value.Value.(*MapInternals).Get(key)
+	}
+	found, ok := value.Value.(collections.Map).Get(key)
+	if !ok {
+		return nil, false
+	}
+	return found.(*OtterValue), true
+}
+
+// How deeply nested collections are hashed. Anything nested deeper
+// doesn't contribute to the hash, which keeps hashing cyclic values
+// finite. Cyclic values that are equal look the same to any fixed
+// depth, so they still hash the same
+const hashDepth = 4
+
+// Hashes a value consistently with ==
+func hashValue(value *OtterValue, depth int) uint32 {
+	// An equals method can find values equal whatever their contents,
+	// so every value that is compared with the same method hashes the
+	// same
+	if method, found := findMethod(value.Type, EqualsMethodName); found {
+		return collections.Hash(reflect.ValueOf(method).Pointer())
+	}
+	var hash uint32
+	switch value.baseType().Value {
+	case TString, TInt, TFloat, TBool, TNull:
+		hash = collections.Hash(value.Value)
+	case TType:
+		hash = collections.Hash(string(value.Value.(TypeName)))
+	case TFunction:
+		hash = collections.Hash(reflect.ValueOf(value.Callable).Pointer())
+	case TArray:
+		hash = hashSequence(value.Value.([]*OtterValue), depth)
+	case TVector:
+		hash = hashSequence(vectorElements(value.Value.(collections.Vector)), depth)
+	case TMap, TPersistentMap:
+		// Summing makes the hash independent of the order of the entries
+		if depth > 0 {
+			for _, entry := range mapEntries(value) {
+				hash += 31*hashValue(entry.Key.(*OtterValue), depth-1) + hashValue(entry.Value.(*OtterValue), depth-1)
+			}
+		}
+	case TSet:
+		if depth > 0 {
+			value.Value.(collections.Set).ForEach(func(element interface{}) {
+				hash += hashValue(element.(*OtterValue), depth-1)
+			})
+		}
+	case TRange:
+		r := value.Value.(*collections.Range)
+		hash = collections.Hash(r.Size())
+		if r.Size() > 0 {
+			hash = 31*hash + collections.Hash(r.Start())
+		}
+		if r.Size() > 1 {
+			hash = 31*hash + collections.Hash(r.Step())
+		}
+	default:
+		if value.Fields == nil {
+			return collections.Hash(reflect.ValueOf(value).Pointer())
+		}
+	}
+	if value.Fields != nil && depth > 0 {
+		hash = 31*hash + collections.Hash(reflect.ValueOf(value.Type).Pointer())
+		for name, field := range value.Fields {
+			hash += 31*collections.Hash(name) + hashValue(field, depth-1)
+		}
+	}
+	return hash
+}
+
+func hashSequence(elements []*OtterValue, depth int) uint32 {
+	hash := collections.Hash(len(elements))
+	if depth == 0 {
+		return hash
+	}
+	for _, element := range elements {
+		hash = 31*hash + hashValue(element, depth-1)
+	}
+	return hash
+}
+
+// The most differences assertDeepEqual reports
+const maxReportedDifferences = 10
+
+// Finds where two values that aren't equal diverge. Each difference
+// is described with a path from the top of the values, like
+// value[1]["name"], and collections are searched for differences
+// in their elements rather than reported whole
+type differ struct {
+	checker     *equalityChecker
+	differences []string
+	visited     map[valuePair]bool
+}
+
+func (interpreter *Interpreter) describeDifferences(actual *OtterValue, expected *OtterValue) ([]string, exception.Exception) {
+	d := &differ{checker: newEqualityChecker(interpreter), visited: map[valuePair]bool{}}
+	err := d.diff("value", actual, expected)
+	return d.differences, err
+}
+
+func (d *differ) report(path string, format string, args ...interface{}) {
+	d.differences = append(d.differences, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (d *differ) diff(path string, actual *OtterValue, expected *OtterValue) exception.Exception {
+	equal, err := d.checker.equal(actual, expected)
+	if err != nil || equal {
+		return err
+	}
+	pair := valuePair{actual, expected}
+	_, hasEquals := findMethod(actual.Type, EqualsMethodName)
+	if actual.Type != expected.Type || hasEquals || d.visited[pair] {
+		d.reportUnequal(path, actual, expected)
+		return nil
+	}
+	d.visited[pair] = true
+	found := len(d.differences)
+	switch actual.baseType().Value {
+	case TArray:
+		err = d.diffSequences(path, actual.Value.([]*OtterValue), expected.Value.([]*OtterValue))
+	case TVector:
+		err = d.diffSequences(path, vectorElements(actual.Value.(collections.Vector)), vectorElements(expected.Value.(collections.Vector)))
+	case TMap, TPersistentMap:
+		err = d.diffEntries(path, actual, expected)
+	case TSet:
+		d.diffSets(path, actual.Value.(collections.Set), expected.Value.(collections.Set))
+	}
+	if err == nil && actual.Fields != nil {
+		err = d.diffFields(path, actual, expected)
+	}
+	if err == nil && len(d.differences) == found {
+		d.reportUnequal(path, actual, expected)
+	}
+	return err
+}
+
+func (d *differ) reportUnequal(path string, actual *OtterValue, expected *OtterValue) {
+	d.report(path, "%v is not equal to %v", elementString(actual, map[*OtterValue]bool{}), elementString(expected, map[*OtterValue]bool{}))
+}
+
+func (d *differ) diffSequences(path string, actual []*OtterValue, expected []*OtterValue) exception.Exception {
+	for i := 0; i < len(actual) || i < len(expected); i++ {
+		elementPath := fmt.Sprintf("%v[%v]", path, i)
+		switch {
+		case i >= len(expected):
+			d.report(elementPath, "unexpected %v", elementString(actual[i], map[*OtterValue]bool{}))
+		case i >= len(actual):
+			d.report(elementPath, "missing %v", elementString(expected[i], map[*OtterValue]bool{}))
+		default:
+			err := d.diff(elementPath, actual[i], expected[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *differ) diffEntries(path string, actual *OtterValue, expected *OtterValue) exception.Exception {
+	for _, entry := range mapEntries(actual) {
+		key := entry.Key.(*OtterValue)
+		entryPath := fmt.Sprintf("%v[%v]", path, elementString(key, map[*OtterValue]bool{}))
+		expectedValue, found := mapLookup(expected, key)
+		if !found {
+			d.report(entryPath, "unexpected %v", elementString(entry.Value.(*OtterValue), map[*OtterValue]bool{}))
+			continue
+		}
+		err := d.diff(entryPath, entry.Value.(*OtterValue), expectedValue)
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range mapEntries(expected) {
+		key := entry.Key.(*OtterValue)
+		if _, found := mapLookup(actual, key); !found {
+			entryPath := fmt.Sprintf("%v[%v]", path, elementString(key, map[*OtterValue]bool{}))
+			d.report(entryPath, "missing %v", elementString(entry.Value.(*OtterValue), map[*OtterValue]bool{}))
+		}
+	}
+	return nil
+}
+
+func (d *differ) diffSets(path string, actual collections.Set, expected collections.Set) {
+	actual.ForEach(func(element interface{}) {
+		if !expected.Contains(element) {
+			d.report(path, "unexpected element %v", elementString(element.(*OtterValue), map[*OtterValue]bool{}))
+		}
+	})
+	expected.ForEach(func(element interface{}) {
+		if !actual.Contains(element) {
+			d.report(path, "missing element %v", elementString(element.(*OtterValue), map[*OtterValue]bool{}))
+		}
+	})
+}
+
+func (d *differ) diffFields(path string, actual *OtterValue, expected *OtterValue) exception.Exception {
+	names := []string{}
+	for name := range actual.Fields {
+		names = append(names, name)
+	}
+	for name := range expected.Fields {
+		if _, found := actual.Fields[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fieldPath := path + "." + name
+		actualField, inActual := actual.Fields[name]
+		expectedField, inExpected := expected.Fields[name]
+		switch {
+		case !inExpected:
+			d.report(fieldPath, "unexpected %v", elementString(actualField, map[*OtterValue]bool{}))
+		case !inActual:
+			d.report(fieldPath, "missing %v", elementString(expectedField, map[*OtterValue]bool{}))
+		default:
+			err := d.diff(fieldPath, actualField, expectedField)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Like assertEqual, but the error describes where the values diverge
+func AssertDeepEqual(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	differences, err := interpreter.describeDifferences(values[0], values[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(differences) == 0 {
+		return interpreter.NewNull(), nil
+	}
+	if len(differences) > maxReportedDifferences {
+		more := len(differences) - maxReportedDifferences
+		differences = append(differences[:maxReportedDifferences], fmt.Sprintf("and %v more", more))
+	}
+	return nil, exception.New(exception.AssertionError, "values differ at "+strings.Join(differences, "; "), 0, 0)
+}
+
+// Returns the hash of a value as an int. Values that are equal have
+// the same hash
+func HashFunction(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return interpreter.NewInt(int64(values[0].Hash())), nil
+}
@@ -0,0 +1,203 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// The exception types that can be raised and caught by Otter code.
+// Each has a global constructor function of the same name
+var builtinExceptionTypes = []exception.ExceptionType{
+	exception.AssertionError,
+	exception.DivideByZeroError,
+	exception.TypeError,
+	exception.NameError,
+	exception.InternalError,
+	exception.MethodError,
+	exception.ArgumentError,
+	exception.IndexError,
+	exception.KeyError,
+	exception.IterationError,
+	exception.ImportError,
+}
+
+// Wraps a Go exception as a first-class Otter value
+func (interpreter *Interpreter) NewException(otterException *exception.OtterException) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TException),
+		Value: otterException,
+	}
+}
+
+// Converts any error into an Otter exception value. Errors that did not
+// originate in Otter are treated as internal errors
+func (interpreter *Interpreter) exceptionValueFromError(err error) *OtterValue {
+	otterException, ok := exception.As(err)
+	if !ok {
+		otterException = exception.Wrap(exception.InternalError, "", err, 0, 0).(*exception.OtterException)
+	}
+	return interpreter.NewException(otterException)
+}
+
+func newExceptionConstructor(exceptionType exception.ExceptionType) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		message := values[0]
+		if !message.IsInstanceOf(TString) {
+			return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v message must be a string, got %v", exceptionType, message.Type.Value), 0, 0)
+		}
+		otterException := exception.New(exceptionType, message.Value.(string), 0, 0).(*exception.OtterException)
+		return interpreter.NewException(otterException), nil
+	}
+}
+
+func ExceptionType(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	otterException := values[0].Value.(*exception.OtterException)
+	return interpreter.NewString(string(otterException.Type)), nil
+}
+
+func ExceptionMessage(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	otterException := values[0].Value.(*exception.OtterException)
+	return interpreter.NewString(otterException.Message), nil
+}
+
+func ExceptionLine(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	otterException := values[0].Value.(*exception.OtterException)
+	return interpreter.NewInt(int64(otterException.Line)), nil
+}
+
+func ExceptionCol(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	otterException := values[0].Value.(*exception.OtterException)
+	return interpreter.NewInt(int64(otterException.Col)), nil
+}
+
+func DefineExceptionType(interpreter *Interpreter) {
+	interpreter.DefineType(TException, NewBuiltInConstructor(TException, 1, newExceptionConstructor(exception.BaseException)))
+	interpreter.DefineBuiltinMethod(TException, "type", 1, ExceptionType)
+	interpreter.DefineBuiltinMethod(TException, "message", 1, ExceptionMessage)
+	interpreter.DefineBuiltinMethod(TException, "line", 1, ExceptionLine)
+	interpreter.DefineBuiltinMethod(TException, "col", 1, ExceptionCol)
+
+	for _, exceptionType := range builtinExceptionTypes {
+		constructor, _ := interpreter.NewBuiltInFunction(string(exceptionType), 1, newExceptionConstructor(exceptionType))
+		interpreter.DefineGlobal(string(exceptionType), constructor)
+	}
+}
+
+func (interpreter *Interpreter) doThrow(tree *parser.Token) (*OtterValue, exception.Exception) {
+	if len(tree.Children) == 0 {
+		return interpreter.rethrow(tree.Line, tree.Col)
+	}
+	value, err := interpreter.Evaluate(tree.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.throw(value, tree.Line, tree.Col)
+}
+
+// Raises an exception value, which is located at line and col if
+// it was created without a location
+func (interpreter *Interpreter) throw(value *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	if !value.IsInstanceOf(TException) {
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("only exceptions can be thrown, got %v", value.Type.Value), line, col)
+	}
+	otterException := value.Value.(*exception.OtterException)
+	if otterException.Line == 0 && otterException.Col == 0 {
+		otterException.Line = line
+		otterException.Col = col
+	}
+	return nil, otterException
+}
+
+// Raises the exception being handled by the current catch block again
+func (interpreter *Interpreter) rethrow(line int, col int) (*OtterValue, exception.Exception) {
+	current := interpreter.CallStack.Peek().Exception
+	if current == nil {
+		return nil, exception.New(exception.SyntaxError, "throw with no exception outside of a catch block", line, col)
+	}
+	return nil, current
+}
+
+// Tests whether a catch clause handles an exception. Catch
+// clauses without a type, or with the base Exception type,
+// handle every exception
+func catchHandles(catch *parser.Token, otterException *exception.OtterException) bool {
+	if len(catch.Children) < 3 {
+		return true
+	}
+	handledType := exception.ExceptionType(catch.Children[2].Value)
+	return handledType == exception.BaseException || handledType == otterException.Type
+}
+
+func (interpreter *Interpreter) doTry(tree *parser.Token) (*OtterValue, exception.Exception) {
+	if len(tree.Children) < 2 {
+		return nil, exception.New(exception.SyntaxError, "invalid try statement", tree.Line, tree.Col)
+	}
+	value, err := interpreter.Evaluate(tree.Children[0])
+
+	var finally *parser.Token
+	handled := false
+	for _, clause := range tree.Children[1:] {
+		if clause.Symbol == parser.Finally {
+			finally = clause
+			continue
+		}
+		if err == nil || handled || isControlFlowSignal(err) {
+			continue
+		}
+		exceptionValue := interpreter.exceptionValueFromError(err)
+		otterException := exceptionValue.Value.(*exception.OtterException)
+		if !catchHandles(clause, otterException) {
+			continue
+		}
+		// An exception raised inside a catch block is not handled
+		// by the catch clauses that follow it
+		handled = true
+		frame := interpreter.CallStack.Peek()
+		previous := frame.Exception
+		frame.Exception = otterException
+		// The caught exception is only visible inside the catch block
+		interpreter.CallStack.PushScope(interpreter.resolution.layouts[clause].names)
+		frame.Environment.Slots[0] = exceptionValue
+		value, err = interpreter.Evaluate(clause.Children[1])
+		interpreter.CallStack.PopScope()
+		frame.Exception = previous
+	}
+
+	if finally != nil {
+		_, finallyErr := interpreter.Evaluate(finally.Children[0])
+		if finallyErr != nil {
+			return nil, finallyErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Builtin functions don't know where they were called from, so raise
+// exceptions at 0:0. Moves such exceptions to the call site
+func locateException(err error, line int, col int) exception.Exception {
+	otterException, ok := exception.As(err)
+	if ok && otterException.Line == 0 && otterException.Col == 0 {
+		otterException.Line = line
+		otterException.Col = col
+	}
+	return err
+}
+
+// Records the call stack on an exception the first time it
+// propagates out of a stack frame
+func (interpreter *Interpreter) recordStackTrace(err error) {
+	otterException, ok := exception.As(err)
+	if !ok || otterException.StackTrace != nil {
+		return
+	}
+	frame := interpreter.CallStack.Peek()
+	if otterException.FileName == "" {
+		otterException.FileName = frame.FileName
+	}
+	otterException.StackTrace = interpreter.CallStack.StackTrace(otterException.Line, otterException.Col)
+}
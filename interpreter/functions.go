@@ -9,6 +9,9 @@ import (
 
 const Variadic = -1
 
+// The name given to functions created by function literals
+const AnonymousFunctionName = "<anonymous>"
+
 func ConstructFunction(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
 	return nil, exception.New(exception.NameError, "function is not callable", 0, 0)
 }
@@ -17,7 +20,7 @@ func ValidateFunctionDefinition(tree *parser.Token) exception.Exception {
 	if tree == nil {
 		return exception.New(exception.InternalError, "null token passed to NewUserDefinedFunction", 0, 0)
 	}
-	if tree.Symbol != parser.FunctionDefinition {
+	if tree.Symbol != parser.FunctionDefinition && tree.Symbol != parser.FunctionLiteral {
 		return exception.New(exception.InternalError, fmt.Sprintf("non function definition token %v passed to NewUserDefinedFunction", tree.Symbol), tree.Line, tree.Col)
 	}
 	return nil
@@ -30,6 +33,7 @@ func (interpreter *Interpreter) NewBuiltInFunction(name string, arity int, built
 		Arity:               arity,
 		BuiltInFunction:     builtIn,
 		UserDefinedFunction: nil,
+		interpreter:         interpreter,
 	}
 	return &OtterValue{
 		Type:     interpreter.MustResolveType(TFunction),
@@ -38,6 +42,13 @@ func (interpreter *Interpreter) NewBuiltInFunction(name string, arity int, built
 	}, nil
 }
 
+// Calls a function implemented in Go. Exceptions raised by equals
+// methods that hashed collections call while it runs are raised by it
+func (interpreter *Interpreter) callBuiltIn(callable *Callable, arguments []*OtterValue) (value *OtterValue, err exception.Exception) {
+	defer recoverKeyException(&err)
+	return callable.BuiltInFunction(interpreter, arguments)
+}
+
 // Gott a come up with a better name here
 func NewBuiltInConstructor(typeName TypeName, arity int, builtIn BuiltInFunction) *Callable {
 	return &Callable{
@@ -53,14 +64,35 @@ func (interpreter *Interpreter) NewUserDefinedFunction(tree *parser.Token) (*Ott
 	if err != nil {
 		return nil, err
 	}
-	functionName := tree.Children[0].Value
-	parameters := tree.Children[1].Children
+	// Function literals are anonymous, so have no name child
+	functionName := AnonymousFunctionName
+	children := tree.Children
+	if tree.Symbol == parser.FunctionDefinition {
+		functionName = children[0].Value
+		children = children[1:]
+	}
+	parameters := children[0].Children
+	frame := interpreter.CallStack.Peek()
 
 	callable := &Callable{
 		UserDefinedFunction: tree,
 		Arity:               len(parameters),
 		BuiltInFunction:     nil,
 		Name:                functionName,
+		FileName:            frame.FileName,
+		Parameters:          parameters,
+		Body:                children[1],
+		Closure:             frame.Environment,
+		locals:              interpreter.resolution.layouts[tree],
+		interpreter:         interpreter,
+	}
+
+	if interpreter.Bytecode {
+		chunk, err := interpreter.functionChunk(tree)
+		if err != nil {
+			return nil, err
+		}
+		callable.Chunk = chunk
 	}
 
 	return &OtterValue{
@@ -70,10 +102,11 @@ func (interpreter *Interpreter) NewUserDefinedFunction(tree *parser.Token) (*Ott
 	}, nil
 }
 
-// Tests if two objects of type 'function' are equal
+// Tests if two objects of type 'function' are equal. Functions
+// are only equal to themselves, since two closures created from the
+// same definition may capture different environments
 func areFunctionsEqual(left *OtterValue, right *OtterValue) bool {
-	// TODO - this is not safe long term
-	return left.Callable.Name == right.Callable.Name
+	return left.Callable == right.Callable
 }
 
 func (interpreter *Interpreter) defineFunction(tree *parser.Token) (*OtterValue, error) {
@@ -82,8 +115,7 @@ func (interpreter *Interpreter) defineFunction(tree *parser.Token) (*OtterValue,
 	if err != nil {
 		return nil, err
 	}
-	// TODO - prevent overriding builtins
-	err = interpreter.CallStack.AssignVariable(udf.Callable.Name, udf)
+	err = interpreter.declareVariable(interpreter.resolution.variables[tree.Children[0]], udf, false, tree.Line, tree.Col)
 	if err != nil {
 		return nil, err
 	}
@@ -93,58 +125,137 @@ func (interpreter *Interpreter) defineFunction(tree *parser.Token) (*OtterValue,
 func (interpreter *Interpreter) invokeCallable(callable *Callable, arguments []*OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 	arity := callable.Arity
 	if arity != Variadic && len(arguments) != arity {
-		return nil, exception.New(exception.TypeError, fmt.Sprintf("%v takes exactly %v arguments, found %v", callable.Name, callable.Arity, len(arguments)), line, col)
+		expected, found := arity, len(arguments)
+		// The value a method is called on isn't one of the
+		// arguments the caller wrote
+		if callable.Method {
+			expected, found = expected-1, found-1
+		}
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("%v takes exactly %v arguments, found %v", callable.Name, expected, found), line, col)
 	}
 	if callable.BuiltInFunction != nil {
-		return callable.BuiltInFunction(interpreter, arguments)
+		value, err := interpreter.callBuiltIn(callable, arguments)
+		if err != nil {
+			return nil, locateException(err, line, col)
+		}
+		return value, nil
+	}
+	var this *OtterValue
+	if callable.Method {
+		this, arguments = arguments[0], arguments[1:]
 	}
-	udf := callable.UserDefinedFunction
-	parameters := udf.Children[1].Children
+	parameters := callable.Parameters
 	if len(parameters) != len(arguments) {
 		return nil, exception.New(exception.TypeError, fmt.Sprintf("%v takes %v arguments, got %v", callable.Name, len(parameters), len(arguments)), line, col)
 	}
-	// TODO: Could this be cleaner
-	stackFrame := NewCallStackFrame(callable.Name)
-	for index, parameter := range parameters {
-		arg := arguments[index]
-		stackFrame.Scope[parameter.Value] = arg
+	// The resolver puts the parameters in the first slots, followed
+	// by this and the class for methods
+	environment := NewLocalEnvironment(callable.Closure, callable.locals.names)
+	copy(environment.Slots, arguments)
+	if this != nil {
+		environment.Slots[len(parameters)] = this
+		environment.Slots[len(parameters)+1] = callable.Class
 	}
+	stackFrame := NewCallStackFrame(callable.Name, environment)
+	stackFrame.FileName = callable.FileName
+	stackFrame.CallLine = line
+	stackFrame.CallCol = col
 	interpreter.CallStack.Push(stackFrame)
-	block := udf.Children[2]
+	// Functions without a return statement return the value
+	// of the last statement executed
+	var lastValue *OtterValue
 	var err error
-	for _, child := range block.Children {
-		_, err = interpreter.Evaluate(child)
-		if err != nil {
-			break
+	if callable.Chunk != nil {
+		lastValue, err = interpreter.run(callable.Chunk)
+	} else {
+		for _, child := range callable.Body.Children {
+			lastValue, err = interpreter.Evaluate(child)
+			if err != nil {
+				break
+			}
 		}
-		stackFrame := interpreter.CallStack.Peek()
-		// TODO handle nil stack frame
+	}
+	if signal, ok := err.(*returnSignal); ok {
+		lastValue, err = signal.value, nil
+	}
+	if err != nil {
+		interpreter.recordStackTrace(err)
+	}
+	interpreter.CallStack.Pop()
+	if err != nil {
+		return nil, err
+	}
+	if lastValue != nil {
+		return lastValue, nil
+	}
+	return interpreter.NewNull(), nil
+}
 
-		if stackFrame.ReturnValue != nil {
-			break
-		}
+// Calls a function value with the given arguments, as if it were
+// called at line and col of the current frame
+func (interpreter *Interpreter) Call(function *OtterValue, arguments []*OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	if function.Callable == nil {
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v is not callable", function.Type.Value), line, col)
 	}
-	frame := interpreter.CallStack.Pop()
+	value, err := interpreter.invokeCallable(function.Callable, arguments, line, col)
 	if err != nil {
+		interpreter.recordStackTrace(err)
 		return nil, err
 	}
-	if frame.ReturnValue == nil {
-		frame.ReturnValue = interpreter.NewNull()
+	return value, nil
+}
+
+// Evaluates a return statement to a signal that unwinds to the
+// enclosing function call. A bare return returns null
+func (interpreter *Interpreter) doReturn(tree *parser.Token) (*OtterValue, exception.Exception) {
+	stackFrame := interpreter.CallStack.Peek()
+	if stackFrame.FunctionName == "global" {
+		return nil, exception.New(exception.SyntaxError, "illegal return in global scope", tree.Line, tree.Col)
+	}
+	value := interpreter.NewNull()
+	if len(tree.Children) > 0 {
+		var err exception.Exception
+		value, err = interpreter.Evaluate(tree.Children[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, &returnSignal{
+		value: value,
+		line:  tree.Line,
+		col:   tree.Col,
+	}
+}
+
+// Returns the name a called expression is described by in errors.
+// Expressions other than names, like fs[0], have no name
+func calleeName(tree *parser.Token) string {
+	if tree.Symbol == parser.Name {
+		return tree.Value
+	}
+	return ""
+}
+
+// Raises the TypeError for calling a value that isn't a function.
+// Values without a name are described by their type
+func notCallable(name string, value *OtterValue, line int, col int) exception.Exception {
+	if name == "" {
+		name = fmt.Sprintf("%v value", value.Type.Value)
 	}
-	return frame.ReturnValue, nil
+	return exception.New(exception.TypeError, fmt.Sprintf("%v is not callable", name), line, col)
 }
 
 // Should probably not be called call function, as it is also the syntax for other calls
 func (interpreter *Interpreter) callFunction(tree *parser.Token) (*OtterValue, exception.Exception) {
 	// TODO - check inputs
-	functionName := tree.Children[0]
-	functionValue, err := interpreter.resolveName(functionName)
+	functionTree := tree.Children[0]
+	functionValue, err := interpreter.Evaluate(functionTree)
 	if err != nil {
 		return nil, err
 	}
 
 	if functionValue.Callable == nil {
-		return nil, exception.New(exception.TypeError, fmt.Sprintf("%v is not callable", functionName.Value), tree.Line, tree.Col)
+		return nil, notCallable(calleeName(functionTree), functionValue, tree.Line, tree.Col)
 	}
 
 	// TODO - optimize memory allocation here
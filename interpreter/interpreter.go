@@ -2,6 +2,8 @@ package interpreter
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/nicholasbailey/otter/exception"
@@ -10,23 +12,69 @@ import (
 
 // TODO - There are a lot of magic strings here
 
-type Scope map[string]*OtterValue
+type Scope map[string]*Binding
 
 func NewScope() Scope {
-	return map[string]*OtterValue{}
+	return map[string]*Binding{}
 }
 
 type Interpreter struct {
 	CallStack CallStack
+	// In strict mode, assigning to a variable that has not been
+	// declared is a NameError rather than an implicit declaration
+	Strict bool
+	// Whether code is run a statement at a time, as in the REPL. Then
+	// functions can refer to globals that later statements define,
+	// and referring to one that is never defined is a NameError when
+	// the function runs
+	Incremental bool
+	// The builtin types by name. Values are created with these rather
+	// than looked up by name, so scripts can use the names of types
+	// for their own variables
+	types map[TypeName]*OtterValue
+	// Values that are shared rather than created each time they are
+	// needed
+	null       *OtterValue
+	trueValue  *OtterValue
+	falseValue *OtterValue
+	smallInts  []*OtterValue
+	// Compares values with == that have no equals method. It is
+	// shared so that equals methods that compare cyclic values
+	// with == still terminate
+	equalityChecker *equalityChecker
+	// Whether code is compiled to bytecode and run by the virtual
+	// machine rather than evaluated by walking its syntax tree
+	Bytecode bool
+	// The compiled bodies of functions, by their definitions
+	chunks map[*parser.Token]*Chunk
+	// Where the names in the code run so far refer to
+	resolution *resolution
+	// Directories searched for modules that aren't in the directory
+	// of the file importing them. Defaults to the directories listed
+	// in the OTTERPATH environment variable
+	ModulePath []string
+	// The modules imported so far, by the absolute paths of their
+	// files or the names of native modules
+	modules map[string]*OtterValue
+	// The modules currently being imported, innermost last
+	importing []*Module
 }
 
 func (interpreter *Interpreter) Execute(statements []*parser.Token) (*OtterValue, exception.Exception) {
+	if err := interpreter.resolve(statements); err != nil {
+		interpreter.recordStackTrace(err)
+		return nil, err
+	}
+	if interpreter.Bytecode {
+		return interpreter.executeBytecode(statements)
+	}
 	var value *OtterValue
 	var err error = nil
 	for _, statement := range statements {
 
 		value, err = interpreter.Evaluate(statement)
 		if err != nil {
+			interpreter.recordStackTrace(err)
 			return nil, err
 		}
 	}
@@ -40,14 +88,13 @@ func (interpreter *Interpreter) Evaluate(tree *parser.Token) (*OtterValue, excep
 	case parser.IntLiteral:
 		parsedInt, err := strconv.ParseInt(tree.Value, 0, 64)
 		if err != nil {
-			// TODO: Make this a proper exception
-			return nil, err
+			return nil, exception.Wrap(exception.SyntaxError, fmt.Sprintf("invalid int literal %v", tree.Value), err, tree.Line, tree.Col)
 		}
 		return interpreter.NewInt(parsedInt), nil
 	case parser.FloatLiteral:
 		parsedFloat, err := strconv.ParseFloat(tree.Value, 64)
 		if err != nil {
-			return nil, err
+			return nil, exception.Wrap(exception.SyntaxError, fmt.Sprintf("invalid float literal %v", tree.Value), err, tree.Line, tree.Col)
 		}
 		return interpreter.NewFloat(parsedFloat), nil
 	case "true":
@@ -55,11 +102,7 @@ func (interpreter *Interpreter) Evaluate(tree *parser.Token) (*OtterValue, excep
 	case "false":
 		return interpreter.False(), nil
 	case parser.Name:
-		value, found := interpreter.CallStack.ResolveVariable(tree.Value)
-		if !found {
-			return nil, fmt.Errorf("syntaxerror: unbound variable %v at line %v, col %v", tree.Value, tree.Line, tree.Col)
-		}
-		return value, nil
+		return interpreter.resolveName(tree)
 	// Handle Variable assignment
 	case "&&":
 		return interpreter.doAnd(tree)
@@ -74,6 +117,9 @@ func (interpreter *Interpreter) Evaluate(tree *parser.Token) (*OtterValue, excep
 	case "+":
 		return interpreter.doAddition(tree)
 	case "-":
+		if len(tree.Children) == 1 {
+			return interpreter.doNegation(tree)
+		}
 		return interpreter.doSubtraction(tree)
 	case "*":
 		return interpreter.doMultiplication(tree)
@@ -91,44 +137,53 @@ func (interpreter *Interpreter) Evaluate(tree *parser.Token) (*OtterValue, excep
 		return interpreter.doGreaterThanOrEqualTo(tree)
 	case parser.While:
 		return interpreter.doWhile(tree)
+	case parser.Break, parser.Continue:
+		return interpreter.doLoopControl(tree)
 	case parser.FunctionDefinition:
 		return interpreter.defineFunction(tree)
+	case parser.FunctionLiteral:
+		return interpreter.NewUserDefinedFunction(tree)
 	case parser.FunctionInvocation:
 		return interpreter.callFunction(tree)
 	case parser.Block:
-		var result *OtterValue
-		var err exception.Exception
-		for _, child := range tree.Children {
-			result, err = interpreter.Evaluate(child)
-			if err != nil {
-				return nil, err
-			}
-		}
-		return result, nil
+		return interpreter.doBlock(tree)
+	case parser.Let, parser.Const:
+		return interpreter.doDeclaration(tree)
 	case "return":
-		stackFrame := interpreter.CallStack.Peek()
-		if stackFrame.FunctionName == "global" {
-			return nil, exception.New(exception.SyntaxError, "illegal return in global scope", tree.Line, tree.Col)
-		}
-		child := tree.Children[0]
-		value, err := interpreter.Evaluate(child)
-		// TODO - stack handle errors
-		if err != nil {
-			return nil, err
-		}
-		stackFrame.ReturnValue = value
-		return value, nil
+		return interpreter.doReturn(tree)
 	case "if":
 		return interpreter.doIf(tree)
 	case parser.Access:
 		return interpreter.doAccess(tree)
+	case parser.Index:
+		return interpreter.doIndex(tree)
+	case parser.ArrayLiteral:
+		return interpreter.doArrayLiteral(tree)
+	case parser.MapLiteral:
+		return interpreter.doMapLiteral(tree)
+	case "instanceof":
+		return interpreter.doInstanceOf(tree)
+	case parser.Class:
+		return interpreter.doClass(tree)
+	case parser.Import:
+		return interpreter.doImport(tree)
+	case parser.FromImport:
+		return interpreter.doFromImport(tree)
+	case parser.Try:
+		return interpreter.doTry(tree)
+	case parser.Throw:
+		return interpreter.doThrow(tree)
 	}
 
-	return nil, fmt.Errorf("syntaxerror: unrecognized symbol '%v' at line %v, col %v", tree.Value, tree.Line, tree.Col)
+	return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unrecognized symbol '%v'", tree.Value), tree.Line, tree.Col)
 }
 
+// Defines a builtin global. Scripts can shadow builtins with their
+// own local variables, but can't assign to or redeclare them
 func (interpreter *Interpreter) DefineGlobal(name string, value *OtterValue) {
-	interpreter.CallStack.Globals().Scope[name] = value
+	binding := interpreter.CallStack.Globals().Environment.binding(name)
+	binding.Value = value
+	binding.Builtin = true
 }
 
 func (interpreter *Interpreter) DefineMethod(typeName TypeName, methodName string, callable *Callable) {
@@ -143,16 +198,26 @@ func (interpreter *Interpreter) DefineBuiltinMethod(
 	builtInFunction BuiltInFunction,
 ) {
 	methodFn, _ := interpreter.NewBuiltInFunction(methodName, arity, builtInFunction)
+	methodFn.Callable.Method = true
 	interpreter.DefineMethod(typeName, methodName, methodFn.Callable)
 }
 
 func NewInterpreter() *Interpreter {
 	interpreter := &Interpreter{
-		CallStack: *NewCallStack(),
+		CallStack:  *NewCallStack(),
+		chunks:     map[*parser.Token]*Chunk{},
+		resolution: newResolution(),
+		types:      map[TypeName]*OtterValue{},
+		smallInts:  make([]*OtterValue, maxSmallInt-minSmallInt+1),
+		ModulePath: filepath.SplitList(os.Getenv("OTTERPATH")),
+		modules:    map[string]*OtterValue{},
 	}
-	globalFrame := NewCallStackFrame("global")
+	interpreter.equalityChecker = newEqualityChecker(interpreter)
+	globalFrame := NewCallStackFrame("global", NewEnvironment(nil))
 	interpreter.CallStack.Push(globalFrame)
 	DefineTypeType(interpreter)
+	// Builtin methods are functions, so the function type comes first
+	interpreter.DefineType(TFunction, NewBuiltInConstructor("function", 0, ConstructFunction))
 
 	// Define built in types
 	DefineStringTypes(interpreter)
@@ -167,11 +232,15 @@ func NewInterpreter() *Interpreter {
 	interpreter.DefineType(TFloat, NewBuiltInConstructor(TFloat, 1, ConstructFloat))
 	interpreter.DefineType(TBool, NewBuiltInConstructor(TBool, 1, ConstructBool))
 	interpreter.DefineType(TNull, NewBuiltInConstructor(TNull, 0, ConstructNull))
-
-	interpreter.DefineType(TFunction, NewBuiltInConstructor("function", 0, ConstructFunction))
 	interpreter.DefineGlobal("true", interpreter.True())
 	interpreter.DefineGlobal("false", interpreter.False())
 	interpreter.DefineGlobal("null", interpreter.NewNull())
+	DefineExceptionType(interpreter)
+	DefineArrayType(interpreter)
+	DefineMapType(interpreter)
+	DefineCollectionTypes(interpreter)
+	DefineEntryIterators(interpreter)
+	DefineModuleType(interpreter)
 	DefineBuiltins(interpreter)
 
 	return interpreter
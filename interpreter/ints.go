@@ -14,7 +14,7 @@ func ConstructInt(interpreter *Interpreter, values []*OtterValue) (*OtterValue,
 	} else if v.IsInstanceOf(TString) {
 		parsedInt, err := strconv.ParseInt(v.Value.(string), 0, 64)
 		if err != nil {
-			return nil, err
+			return nil, exception.Wrap(exception.ArgumentError, fmt.Sprintf("cannot convert %v to int", v.Value), err, 0, 0)
 		}
 		return interpreter.NewInt(parsedInt), nil
 	} else {
@@ -23,7 +23,25 @@ func ConstructInt(interpreter *Interpreter, values []*OtterValue) (*OtterValue,
 	}
 }
 
+// Ints in this range are shared rather than created each time they
+// are needed, since they are used far more than others
+const (
+	minSmallInt = -128
+	maxSmallInt = 1023
+)
+
 func (interpreter *Interpreter) NewInt(i int64) *OtterValue {
+	if i < minSmallInt || i > maxSmallInt {
+		return interpreter.newInt(i)
+	}
+	cached := &interpreter.smallInts[i-minSmallInt]
+	if *cached == nil {
+		*cached = interpreter.newInt(i)
+	}
+	return *cached
+}
+
+func (interpreter *Interpreter) newInt(i int64) *OtterValue {
 	return &OtterValue{
 		Type:     interpreter.MustResolveType(TInt),
 		Value:    i,
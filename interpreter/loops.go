@@ -9,12 +9,30 @@ func (interpreter *Interpreter) doFor(tree *parser.Token) (*OtterValue, error) {
 	return nil, nil
 }
 
+func (interpreter *Interpreter) doLoopControl(tree *parser.Token) (*OtterValue, exception.Exception) {
+	signal := &loopSignal{
+		symbol: tree.Symbol,
+		line:   tree.Line,
+		col:    tree.Col,
+	}
+	if len(tree.Children) > 0 {
+		signal.label = tree.Children[0].Value
+	}
+	return nil, signal
+}
+
+// A while loop's children are its condition, its block and
+// optionally a label
 func (interpreter *Interpreter) doWhile(tree *parser.Token) (*OtterValue, error) {
-	if len(tree.Children) != 2 {
+	if len(tree.Children) != 2 && len(tree.Children) != 3 {
 		return nil, exception.New(exception.SyntaxError, "invalid while block", tree.Line, tree.Col)
 	}
 	expression := tree.Children[0]
 	block := tree.Children[1]
+	label := ""
+	if len(tree.Children) == 3 {
+		label = tree.Children[2].Value
+	}
 	retVal := interpreter.NewNull()
 	for {
 		expressionRes, err := interpreter.Evaluate(expression)
@@ -25,10 +43,18 @@ func (interpreter *Interpreter) doWhile(tree *parser.Token) (*OtterValue, error)
 		if expressionTruthiness.Value == false {
 			break
 		}
-		retVal, err = interpreter.Evaluate(block)
-		if err != nil {
+		blockVal, err := interpreter.Evaluate(block)
+		if err == nil {
+			retVal = blockVal
+			continue
+		}
+		signal, ok := err.(*loopSignal)
+		if !ok || (signal.label != "" && signal.label != label) {
 			return nil, err
 		}
+		if signal.symbol == parser.Break {
+			break
+		}
 	}
 	return retVal, nil
 }
@@ -1 +1,255 @@
 package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// Maps are mutable dictionaries that remember the order in which
+// keys were first inserted. Keys are compared structurally, so any
+// value can be a key, but changing a key after adding it to a map
+// will stop it being found
+
+type mapEntry struct {
+	Key   *OtterValue
+	Value *OtterValue
+}
+
+type MapInternals struct {
+	// Entries grouped by the hash of their keys
+	entries map[uint32][]*mapEntry
+	// Entries in insertion order
+	order []*mapEntry
+}
+
+func newMapInternals() *MapInternals {
+	return &MapInternals{
+		entries: map[uint32][]*mapEntry{},
+		order:   []*mapEntry{},
+	}
+}
+
+func (internals *MapInternals) find(key *OtterValue) (*mapEntry, bool) {
+	for _, entry := range internals.entries[key.Hash()] {
+		if key.Equals(entry.Key) {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+func (internals *MapInternals) Get(key *OtterValue) (*OtterValue, bool) {
+	entry, found := internals.find(key)
+	if !found {
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+func (internals *MapInternals) Set(key *OtterValue, value *OtterValue) {
+	if entry, found := internals.find(key); found {
+		entry.Value = value
+		return
+	}
+	entry := &mapEntry{Key: key, Value: value}
+	hash := key.Hash()
+	internals.entries[hash] = append(internals.entries[hash], entry)
+	internals.order = append(internals.order, entry)
+}
+
+func (internals *MapInternals) Remove(key *OtterValue) bool {
+	entry, found := internals.find(key)
+	if !found {
+		return false
+	}
+	hash := key.Hash()
+	bucket := internals.entries[hash]
+	for i, e := range bucket {
+		if e == entry {
+			bucket = append(bucket[:i:i], bucket[i+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(internals.entries, hash)
+	} else {
+		internals.entries[hash] = bucket
+	}
+	for i, e := range internals.order {
+		if e == entry {
+			internals.order = append(internals.order[:i], internals.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Returns the keys of the map in insertion order
+func (internals *MapInternals) Keys() []*OtterValue {
+	keys := make([]*OtterValue, 0, len(internals.order))
+	for _, entry := range internals.order {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+func (interpreter *Interpreter) NewMap(internals *MapInternals) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TMap),
+		Value: internals,
+	}
+}
+
+// Constructs a map from alternating keys and values. Map literals
+// construct maps with it too
+func ConstructMap(interpreter *Interpreter, values []*OtterValue) (value *OtterValue, err exception.Exception) {
+	defer recoverKeyException(&err)
+	if len(values)%2 != 0 {
+		return nil, exception.New(exception.ArgumentError, "Map takes alternating keys and values", 0, 0)
+	}
+	internals := newMapInternals()
+	for i := 0; i < len(values); i += 2 {
+		internals.Set(values[i], values[i+1])
+	}
+	return interpreter.NewMap(internals), nil
+}
+
+func MapLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	return interpreter.NewInt(int64(len(internals.order))), nil
+}
+
+func MapGetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	value, found := internals.Get(values[1])
+	if !found {
+		asString, _ := ConstructString(interpreter, []*OtterValue{values[1]})
+		return nil, exception.New(exception.KeyError, fmt.Sprintf("key %v not found", asString.Value), 0, 0)
+	}
+	return value, nil
+}
+
+func MapSetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	internals.Set(values[1], values[2])
+	return values[2], nil
+}
+
+// Returns the value for a key, or a default if the key is not
+// present. The default is null if not given
+func MapGet(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values) < 2 || len(values) > 3 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("get takes 1 or 2 arguments, got %v", len(values)-1), 0, 0)
+	}
+	internals := values[0].Value.(*MapInternals)
+	value, found := internals.Get(values[1])
+	if found {
+		return value, nil
+	}
+	if len(values) == 3 {
+		return values[2], nil
+	}
+	return interpreter.NewNull(), nil
+}
+
+func MapContains(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	_, found := internals.Get(values[1])
+	return interpreter.NewBool(found), nil
+}
+
+// Removes a key from the map. Returns whether the key was present
+func MapRemove(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	return interpreter.NewBool(internals.Remove(values[1])), nil
+}
+
+func MapKeys(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	return interpreter.NewArray(internals.Keys()), nil
+}
+
+func MapValues(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	mapValues := make([]*OtterValue, 0, len(internals.order))
+	for _, entry := range internals.order {
+		mapValues = append(mapValues, entry.Value)
+	}
+	return interpreter.NewArray(mapValues), nil
+}
+
+type MapIteratorInternals struct {
+	// A snapshot of the keys when iteration started, so
+	// the map can be modified while it is iterated over
+	Keys  []*OtterValue
+	Index int
+}
+
+// Iterates over the keys of the map in insertion order
+func MapIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	return &OtterValue{
+		Type: interpreter.MustResolveType(TMapIterator),
+		Value: &MapIteratorInternals{
+			Keys:  internals.Keys(),
+			Index: 0,
+		},
+	}, nil
+}
+
+func MapIteratorHasNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapIteratorInternals)
+	return interpreter.NewBool(internals.Index < len(internals.Keys)), nil
+}
+
+func MapIteratorGetNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapIteratorInternals)
+	if internals.Index >= len(internals.Keys) {
+		return nil, exception.New(exception.IterationError, "iterable has no more elements", 0, 0)
+	}
+	key := internals.Keys[internals.Index]
+	internals.Index = internals.Index + 1
+	return key, nil
+}
+
+// Iterates over [key, value] arrays in insertion order. Like
+// iterator, this works from a snapshot of the map's entries
+func MapEntryIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	internals := values[0].Value.(*MapInternals)
+	entries := make([]*OtterValue, 0, len(internals.order))
+	for _, entry := range internals.order {
+		entries = append(entries, interpreter.NewArray([]*OtterValue{entry.Key, entry.Value}))
+	}
+	return ArrayIterator(interpreter, []*OtterValue{interpreter.NewArray(entries)})
+}
+
+func DefineMapType(interpreter *Interpreter) {
+	interpreter.DefineType(TMap, NewBuiltInConstructor(TMap, Variadic, ConstructMap))
+	interpreter.DefineBuiltinMethod(TMap, "length", 1, MapLength)
+	interpreter.DefineBuiltinMethod(TMap, "getItem", 2, MapGetItem)
+	interpreter.DefineBuiltinMethod(TMap, "setItem", 3, MapSetItem)
+	interpreter.DefineBuiltinMethod(TMap, "get", Variadic, MapGet)
+	interpreter.DefineBuiltinMethod(TMap, "contains", 2, MapContains)
+	interpreter.DefineBuiltinMethod(TMap, "remove", 2, MapRemove)
+	interpreter.DefineBuiltinMethod(TMap, "keys", 1, MapKeys)
+	interpreter.DefineBuiltinMethod(TMap, "values", 1, MapValues)
+	interpreter.DefineBuiltinMethod(TMap, "iterator", 1, MapIterator)
+
+	interpreter.DefineType(TMapIterator, NewBuiltInConstructor(TMapIterator, 1, MapIterator))
+	interpreter.DefineBuiltinMethod(TMapIterator, "hasNext", 1, MapIteratorHasNext)
+	interpreter.DefineBuiltinMethod(TMapIterator, "getNext", 1, MapIteratorGetNext)
+}
+
+func (interpreter *Interpreter) doMapLiteral(tree *parser.Token) (*OtterValue, exception.Exception) {
+	entries := make([]*OtterValue, len(tree.Children))
+	for i, child := range tree.Children {
+		value, err := interpreter.Evaluate(child)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = value
+	}
+	return ConstructMap(interpreter, entries)
+}
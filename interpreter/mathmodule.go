@@ -0,0 +1,138 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+func init() {
+	RegisterModule("math", DefineMathModule)
+}
+
+// Functions of the math module that take a number and return a float
+var mathFloatFunctions = map[string]func(float64) float64{
+	"sqrt": math.Sqrt,
+	"exp":  math.Exp,
+	"log":  math.Log,
+	"sin":  math.Sin,
+	"cos":  math.Cos,
+	"tan":  math.Tan,
+}
+
+// Functions of the math module that round a number to an int
+var mathRoundingFunctions = map[string]func(float64) float64{
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"round": math.Round,
+}
+
+// Defines the math module, which holds mathematical constants and
+// functions on numbers. The functions accept ints and floats
+func DefineMathModule(module *ModuleBuilder) exception.Exception {
+	module.Export("pi", module.Interpreter.NewFloat(math.Pi))
+	module.Export("e", module.Interpreter.NewFloat(math.E))
+	for name, function := range mathFloatFunctions {
+		module.Function(name, 1, mathFloatFunction(name, function))
+	}
+	for name, function := range mathRoundingFunctions {
+		module.Function(name, 1, mathRoundingFunction(name, function))
+	}
+	module.Function("pow", 2, MathPow)
+	module.Function("abs", 1, MathAbs)
+	module.Function("min", 2, MathMin)
+	module.Function("max", 2, MathMax)
+	return nil
+}
+
+// Returns a number as a float
+func numberArgument(function string, value *OtterValue) (float64, exception.Exception) {
+	switch x := value.Value.(type) {
+	case int64:
+		return float64(x), nil
+	case float64:
+		return x, nil
+	}
+	return 0, exception.New(exception.ArgumentError, fmt.Sprintf("%v expects a number, got %v", function, value.Type.Value), 0, 0)
+}
+
+func mathFloatFunction(name string, function func(float64) float64) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		x, err := numberArgument(name, values[0])
+		if err != nil {
+			return nil, err
+		}
+		return interpreter.NewFloat(function(x)), nil
+	}
+}
+
+func mathRoundingFunction(name string, function func(float64) float64) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		x, err := numberArgument(name, values[0])
+		if err != nil {
+			return nil, err
+		}
+		rounded := function(x)
+		// Converting floats outside the range of int64 doesn't fail,
+		// but gives an undefined int
+		if math.IsNaN(rounded) || rounded >= 9223372036854775808.0 || rounded < -9223372036854775808.0 {
+			return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v can't round %v to an int", name, x), 0, 0)
+		}
+		return interpreter.NewInt(int64(rounded)), nil
+	}
+}
+
+func MathPow(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	x, err := numberArgument("pow", values[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := numberArgument("pow", values[1])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewFloat(math.Pow(x, y)), nil
+}
+
+// Returns the absolute value of a number, of the same type as the number
+func MathAbs(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if i, isInt := values[0].Value.(int64); isInt {
+		if i < 0 {
+			return interpreter.NewInt(-i), nil
+		}
+		return values[0], nil
+	}
+	x, err := numberArgument("abs", values[0])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewFloat(math.Abs(x)), nil
+}
+
+// Returns whichever of two numbers is smaller
+func MathMin(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return compareNumbers("min", values, func(x float64, y float64) bool { return y < x })
+}
+
+// Returns whichever of two numbers is larger
+func MathMax(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return compareNumbers("max", values, func(x float64, y float64) bool { return y > x })
+}
+
+// Returns the second of two numbers if prefer is true of them, or the
+// first otherwise
+func compareNumbers(function string, values []*OtterValue, prefer func(x float64, y float64) bool) (*OtterValue, exception.Exception) {
+	x, err := numberArgument(function, values[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := numberArgument(function, values[1])
+	if err != nil {
+		return nil, err
+	}
+	if prefer(x, y) {
+		return values[1], nil
+	}
+	return values[0], nil
+}
@@ -10,36 +10,109 @@ import (
 func (interpreter *Interpreter) doAccess(tree *parser.Token) (*OtterValue, exception.Exception) {
 	valueTree := tree.Children[0]
 	targetTree := tree.Children[1]
+	if valueTree.Symbol == parser.Name && valueTree.Value == SuperName {
+		return interpreter.doSuperAccess(tree)
+	}
 	value, err := interpreter.Evaluate(valueTree)
 	if err != nil {
 		return nil, err
 	}
-	var methodName string
+	if targetTree.Symbol == parser.FunctionInvocation {
+		arguments, err := interpreter.evaluateArguments(targetTree.Children[1:])
+		if err != nil {
+			return nil, err
+		}
+		return interpreter.callMember(value, targetTree.Children[0].Value, arguments, targetTree.Line, targetTree.Col)
+	}
+	return interpreter.getMember(value, targetTree.Value, targetTree.Line, targetTree.Col)
+}
+
+// Evaluates value.name, which is the field name if the value has
+// one, or otherwise the result of calling the method name
+func (interpreter *Interpreter) getMember(value *OtterValue, name string, line int, col int) (*OtterValue, exception.Exception) {
+	// Fields take precedence over methods with the same name
+	if field, found := value.field(name); found {
+		return field, nil
+	}
+	return interpreter.callMethod(value, name, []*OtterValue{}, line, col)
+}
+
+// Evaluates value.name(arguments...)
+func (interpreter *Interpreter) callMember(value *OtterValue, name string, arguments []*OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	// A field holding a function is called without a receiver
+	if field, found := value.field(name); found {
+		if field.Callable == nil {
+			return nil, exception.New(exception.TypeError, fmt.Sprintf("%v is not callable", name), line, col)
+		}
+		return interpreter.invokeCallable(field.Callable, arguments, line, col)
+	}
+	return interpreter.callMethod(value, name, arguments, line, col)
+}
+
+// Returns the field of a value with the given name. The fields
+// of a module are its exports
+func (value *OtterValue) field(name string) (*OtterValue, bool) {
+	if module, ok := value.Value.(*Module); ok {
+		return module.export(name)
+	}
+	field, found := value.Fields[name]
+	return field, found
+}
+
+func (interpreter *Interpreter) evaluateArguments(tokens []*parser.Token) ([]*OtterValue, exception.Exception) {
 	arguments := []*OtterValue{}
-	if targetTree.Symbol == parser.Name {
-		methodName = targetTree.Value
-	} else if targetTree.Symbol == parser.FunctionInvocation {
-		methodName = targetTree.Children[0].Value
-		for _, childToken := range targetTree.Children[1:] {
-			childValue, err := interpreter.Evaluate(childToken)
-			if err != nil {
-				return nil, err
-			}
-			arguments = append(arguments, childValue)
+	for _, childToken := range tokens {
+		childValue, err := interpreter.Evaluate(childToken)
+		if err != nil {
+			return nil, err
 		}
+		arguments = append(arguments, childValue)
 	}
-	return interpreter.callMethod(value, methodName, arguments)
+	return arguments, nil
 }
 
-func (interpreter *Interpreter) callMethod(value *OtterValue, methodName string, arguments []*OtterValue) (*OtterValue, exception.Exception) {
+func (interpreter *Interpreter) callMethod(value *OtterValue, methodName string, arguments []*OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 
-	method, found := value.Type.Methods[methodName]
+	method, found := findMethod(value.Type, methodName)
 	if !found {
-		// TODO - handle line and col
-		return nil, exception.New(exception.MethodError, fmt.Sprintf("%v has no method %v", value.Type.Value, methodName), 0, 0)
+		return nil, exception.New(exception.MethodError, fmt.Sprintf("%v has no method %v", value.Type.Value, methodName), line, col)
+	}
+	// Builtin methods expect the builtin value set up by super.init
+	if method.BuiltInFunction != nil && value.Fields != nil && value.Value == nil {
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("%v.init must call super.init before %v can be called", value.Type.Value, methodName), line, col)
 	}
 	fullArguments := []*OtterValue{value}
 
 	fullArguments = append(fullArguments, arguments...)
-	return interpreter.invokeCallable(method, fullArguments, 0, 0)
+	return interpreter.invokeCallable(method, fullArguments, line, col)
+}
+
+// Evaluates target[index] by calling the target's getItem method
+func (interpreter *Interpreter) doIndex(tree *parser.Token) (*OtterValue, exception.Exception) {
+	target, err := interpreter.Evaluate(tree.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := interpreter.Evaluate(tree.Children[1])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.callMethod(target, "getItem", []*OtterValue{index}, tree.Line, tree.Col)
+}
+
+// Evaluates target[index] = value by calling the target's setItem method
+func (interpreter *Interpreter) doIndexAssignment(indexTree *parser.Token, valueTree *parser.Token) (*OtterValue, exception.Exception) {
+	target, err := interpreter.Evaluate(indexTree.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := interpreter.Evaluate(indexTree.Children[1])
+	if err != nil {
+		return nil, err
+	}
+	value, err := interpreter.Evaluate(valueTree)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.callMethod(target, "setItem", []*OtterValue{index, value}, indexTree.Line, indexTree.Col)
 }
@@ -0,0 +1,199 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// The extension of Otter source files. Module paths without an
+// extension refer to files with this one
+const SourceExtension = ".otter"
+
+// A Module is a file of Otter code imported by another, or a native
+// module implemented in Go. A module runs in its own global scope,
+// once, the first time it is imported. The globals it defines are its
+// exports, which are read with . on the module value
+type Module struct {
+	Name string
+	// The file the module was loaded from. Empty for native modules
+	FileName string
+	// The module's global environment. Exports are read from its
+	// bindings, so importers see later assignments to them
+	globals *Environment
+}
+
+// Returns the export of a module with the given name. Builtins and
+// the hidden variables the parser introduces, whose names start
+// with ~, aren't exported
+func (module *Module) export(name string) (*OtterValue, bool) {
+	binding, found := module.globals.Scope[name]
+	if !found || binding.Builtin || binding.Value == nil || strings.HasPrefix(name, "~") {
+		return nil, false
+	}
+	return binding.Value, true
+}
+
+func ConstructModule(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return nil, exception.New(exception.TypeError, "modules can only be created by importing them", 0, 0)
+}
+
+func DefineModuleType(interpreter *Interpreter) {
+	interpreter.DefineType(TModule, NewBuiltInConstructor(TModule, 0, ConstructModule))
+}
+
+// Module values have no fields. Their exports can only be
+// assigned to by the module's own code
+func (interpreter *Interpreter) newModule(module *Module) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TModule),
+		Value: module,
+	}
+}
+
+func (interpreter *Interpreter) doImport(tree *parser.Token) (*OtterValue, exception.Exception) {
+	module, err := interpreter.importModule(tree.Children[0].Value, tree.Line, tree.Col)
+	if err != nil {
+		return nil, err
+	}
+	name := tree.Children[1]
+	err = interpreter.declareVariable(interpreter.resolution.variables[name], module, false, name.Line, name.Col)
+	if err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+func (interpreter *Interpreter) doFromImport(tree *parser.Token) (*OtterValue, exception.Exception) {
+	module, err := interpreter.importModule(tree.Children[0].Value, tree.Line, tree.Col)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range tree.Children[1:] {
+		value, err := importName(module, name.Value, name.Line, name.Col)
+		if err != nil {
+			return nil, err
+		}
+		err = interpreter.declareVariable(interpreter.resolution.variables[name], value, false, name.Line, name.Col)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return module, nil
+}
+
+// Returns one of a module's exports
+func importName(module *OtterValue, name string, line int, col int) (*OtterValue, exception.Exception) {
+	value, found := module.Value.(*Module).export(name)
+	if !found {
+		return nil, exception.New(exception.ImportError, fmt.Sprintf("module %v has no export %v", module.Value.(*Module).Name, name), line, col)
+	}
+	return value, nil
+}
+
+// Returns the module with a name or at a path, running it if it
+// hasn't been imported before
+func (interpreter *Interpreter) importModule(modulePath string, line int, col int) (*OtterValue, exception.Exception) {
+	if native, found := findNativeModule(modulePath); found {
+		return interpreter.importNativeModule(native, line, col)
+	}
+	fileName, found := interpreter.findModule(modulePath)
+	if !found {
+		return nil, exception.New(exception.ImportError, fmt.Sprintf("cannot find module %v", modulePath), line, col)
+	}
+	key, pathErr := filepath.Abs(fileName)
+	if pathErr != nil {
+		return nil, exception.Wrap(exception.ImportError, "", pathErr, line, col)
+	}
+	if module, found := interpreter.modules[key]; found {
+		return module, nil
+	}
+	for i, module := range interpreter.importing {
+		if module.FileName != key {
+			continue
+		}
+		cycle := []string{}
+		for _, importing := range interpreter.importing[i:] {
+			cycle = append(cycle, importing.Name)
+		}
+		cycle = append(cycle, module.Name)
+		return nil, exception.New(exception.ImportError, fmt.Sprintf("circular import: %v", strings.Join(cycle, " -> ")), line, col)
+	}
+
+	file, fileErr := os.Open(fileName)
+	if fileErr != nil {
+		return nil, exception.Wrap(exception.ImportError, "", fileErr, line, col)
+	}
+	defer file.Close()
+
+	module := &Module{
+		Name:     strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName)),
+		FileName: key,
+		globals:  interpreter.newModuleEnvironment(),
+	}
+	interpreter.importing = append(interpreter.importing, module)
+	defer func() {
+		interpreter.importing = interpreter.importing[:len(interpreter.importing)-1]
+	}()
+
+	frame := NewCallStackFrame("global", module.globals)
+	frame.FileName = fileName
+	frame.CallLine = line
+	frame.CallCol = col
+	interpreter.CallStack.Push(frame)
+	defer interpreter.CallStack.Pop()
+	statements, err := parser.NewParser(file).Statements()
+	if err != nil {
+		interpreter.recordStackTrace(err)
+		return nil, err
+	}
+	if _, err := interpreter.Execute(statements); err != nil {
+		return nil, err
+	}
+
+	value := interpreter.newModule(module)
+	interpreter.modules[key] = value
+	return value, nil
+}
+
+// Finds the file a module path refers to. Relative paths are looked
+// for in the directory of the file importing them, then in each
+// directory of the module path
+func (interpreter *Interpreter) findModule(modulePath string) (string, bool) {
+	if filepath.Ext(modulePath) == "" {
+		modulePath += SourceExtension
+	}
+	modulePath = filepath.FromSlash(modulePath)
+	if filepath.IsAbs(modulePath) {
+		return modulePath, isFile(modulePath)
+	}
+	directories := append([]string{filepath.Dir(interpreter.CallStack.Peek().FileName)}, interpreter.ModulePath...)
+	for _, directory := range directories {
+		fileName := filepath.Join(directory, modulePath)
+		if isFile(fileName) {
+			return fileName, true
+		}
+	}
+	return "", false
+}
+
+func isFile(fileName string) bool {
+	info, err := os.Stat(fileName)
+	return err == nil && !info.IsDir()
+}
+
+// Creates the global environment of a module, which starts out with
+// only the builtins
+func (interpreter *Interpreter) newModuleEnvironment() *Environment {
+	env := NewEnvironment(nil)
+	for name, binding := range interpreter.CallStack.Globals().Environment.Scope {
+		if binding.Builtin {
+			env.Scope[name] = binding
+		}
+	}
+	return env
+}
@@ -8,10 +8,56 @@ import (
 )
 
 func (intepreter *Interpreter) resolveName(name *parser.Token) (*OtterValue, exception.Exception) {
-	val, found := intepreter.CallStack.ResolveVariable(name.Value)
-	if found {
-		return val, nil
+	return intepreter.loadVariable(intepreter.resolution.variables[name], name.Line, name.Col)
+}
+
+// Returns the environment of the current frame that holds a local
+// variable
+func (interpreter *Interpreter) environmentOf(v *variable) *Environment {
+	env := interpreter.CallStack.Peek().Environment
+	for i := v.depth; i > 0; i-- {
+		env = env.Parent
+	}
+	return env
+}
+
+func (interpreter *Interpreter) loadVariable(v *variable, line int, col int) (*OtterValue, exception.Exception) {
+	var value *OtterValue
+	if v.global != nil {
+		value = v.global.Value
+	} else {
+		value = interpreter.environmentOf(v).Slots[v.slot]
+	}
+	// Variables that haven't been assigned yet don't exist
+	if value == nil {
+		return nil, exception.New(exception.NameError, fmt.Sprintf("%v is not defined", v.name), line, col)
+	}
+	return value, nil
+}
+
+func (interpreter *Interpreter) storeVariable(v *variable, value *OtterValue, line int, col int) exception.Exception {
+	if v.global != nil && v.global.Builtin {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot assign to builtin %v", v.name), line, col)
+	}
+	if v.constant || (v.global != nil && v.global.Constant) {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot assign to constant %v", v.name), line, col)
+	}
+	if v.global != nil {
+		v.global.Value = value
 	} else {
-		return nil, exception.New(exception.NameError, fmt.Sprintf("%v is not defined", name.Value), name.Line, name.Col)
+		interpreter.environmentOf(v).Slots[v.slot] = value
+	}
+	return nil
+}
+
+// Sets the variable a declaration declares, in the current scope
+func (interpreter *Interpreter) declareVariable(v *variable, value *OtterValue, constant bool, line int, col int) exception.Exception {
+	if v.global != nil {
+		return declareBinding(v.global, v.name, value, constant, line, col)
+	}
+	if v.constant {
+		return exception.New(exception.TypeError, fmt.Sprintf("cannot redeclare constant %v", v.name), line, col)
 	}
+	interpreter.CallStack.Peek().Environment.Slots[v.slot] = value
+	return nil
 }
@@ -0,0 +1,102 @@
+package interpreter
+
+import (
+	"sync"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// A NativeModule is a module implemented in Go rather than Otter.
+// Scripts import it by name, like 'import math;'. Each interpreter
+// defines the module the first time a script it runs imports it, so
+// interpreters don't share a native module's values
+type NativeModule struct {
+	Name string
+	// Defines the module's exports
+	Define func(module *ModuleBuilder) exception.Exception
+}
+
+var nativeModules = struct {
+	sync.RWMutex
+	byName map[string]*NativeModule
+}{byName: map[string]*NativeModule{}}
+
+// Registers a module implemented in Go, so scripts run by any
+// interpreter can import it. Packages that provide modules usually
+// register them in an init function. A module registered with the
+// same name as an earlier one replaces it. Native modules are found
+// before modules in files
+func RegisterModule(name string, define func(module *ModuleBuilder) exception.Exception) {
+	nativeModules.Lock()
+	defer nativeModules.Unlock()
+	nativeModules.byName[name] = &NativeModule{Name: name, Define: define}
+}
+
+func findNativeModule(name string) (*NativeModule, bool) {
+	nativeModules.RLock()
+	defer nativeModules.RUnlock()
+	module, found := nativeModules.byName[name]
+	return module, found
+}
+
+// A ModuleBuilder collects the exports of a native module while the
+// module is defined
+type ModuleBuilder struct {
+	// The interpreter the module is being defined for
+	Interpreter *Interpreter
+	Name        string
+	exports     map[string]*OtterValue
+}
+
+// Exports a value, such as a constant, from the module
+func (module *ModuleBuilder) Export(name string, value *OtterValue) {
+	module.exports[name] = value
+}
+
+// Exports a function implemented in Go from the module
+func (module *ModuleBuilder) Function(name string, arity int, builtIn BuiltInFunction) {
+	function, _ := module.Interpreter.NewBuiltInFunction(name, arity, builtIn)
+	module.Export(name, function)
+}
+
+// Exports a type from the module. Calling the type calls constructor.
+// Values of the type are created by giving them the returned type
+func (module *ModuleBuilder) Type(name string, arity int, constructor BuiltInFunction) *OtterValue {
+	t := module.Interpreter.NewType(TypeName(name), NewBuiltInConstructor(TypeName(name), arity, constructor))
+	module.Export(name, t)
+	return t
+}
+
+// Adds a method implemented in Go to a type the module exports. Like
+// builtin methods, it takes the value it is called on as its first
+// argument
+func (module *ModuleBuilder) Method(t *OtterValue, name string, arity int, builtIn BuiltInFunction) {
+	method, _ := module.Interpreter.NewBuiltInFunction(name, arity, builtIn)
+	method.Callable.Method = true
+	t.Methods[name] = method.Callable
+}
+
+// Returns a native module, defining it if this interpreter hasn't
+// imported it before
+func (interpreter *Interpreter) importNativeModule(native *NativeModule, line int, col int) (*OtterValue, exception.Exception) {
+	if module, found := interpreter.modules[native.Name]; found {
+		return module, nil
+	}
+	builder := &ModuleBuilder{
+		Interpreter: interpreter,
+		Name:        native.Name,
+		exports:     map[string]*OtterValue{},
+	}
+	if err := native.Define(builder); err != nil {
+		return nil, locateException(err, line, col)
+	}
+	// Native modules can't assign to their exports once they're
+	// defined, so they're held as constants
+	globals := NewEnvironment(nil)
+	for name, export := range builder.exports {
+		globals.Scope[name] = &Binding{Value: export, Constant: true}
+	}
+	value := interpreter.newModule(&Module{Name: native.Name, globals: globals})
+	interpreter.modules[native.Name] = value
+	return value, nil
+}
@@ -6,9 +6,14 @@ func ConstructNull(interpreter *Interpreter, values []*OtterValue) (*OtterValue,
 	return interpreter.NewNull(), nil
 }
 
+// Returns the null value. There is only one null value per interpreter,
+// created the first time it is needed
 func (interpreter *Interpreter) NewNull() *OtterValue {
-	return &OtterValue{
-		Type:  interpreter.MustResolveType(TNull),
-		Value: nil,
+	if interpreter.null == nil {
+		interpreter.null = &OtterValue{
+			Type:  interpreter.MustResolveType(TNull),
+			Value: nil,
+		}
 	}
+	return interpreter.null
 }
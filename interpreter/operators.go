@@ -28,16 +28,7 @@ func (interpreter *Interpreter) doLessThan(tree *parser.Token) (*OtterValue, err
 	if err != nil {
 		return nil, err
 	}
-	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
-		return interpreter.NewBool(leftValue.Value.(int64) < rightValue.Value.(int64)), nil
-	} else if leftValue.IsInstanceOf(TFloat) && rightValue.IsInstanceOf(TFloat) {
-		return interpreter.NewBool(leftValue.Value.(float64) < rightValue.Value.(float64)), nil
-	} else if leftValue.IsInstanceOf(TString) && rightValue.IsInstanceOf(TString) {
-		return interpreter.NewBool(leftValue.Value.(string) < rightValue.Value.(string)), nil
-	} else if leftValue.Type == rightValue.Type {
-		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v cannot be compared with <", rightValue.Type), tree.Line, tree.Col)
-	}
-	return nil, exception.New(exception.TypeError, "attempted to compare incomparable types with <", tree.Line, tree.Col)
+	return interpreter.compare("<", leftValue, rightValue, tree.Line, tree.Col)
 }
 
 func (interpreter *Interpreter) doGreaterThan(tree *parser.Token) (*OtterValue, error) {
@@ -45,52 +36,44 @@ func (interpreter *Interpreter) doGreaterThan(tree *parser.Token) (*OtterValue,
 	if err != nil {
 		return nil, err
 	}
-	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
-		return interpreter.NewBool(leftValue.Value.(int64) > rightValue.Value.(int64)), nil
-	} else if leftValue.IsInstanceOf(TFloat) && rightValue.IsInstanceOf(TFloat) {
-		return interpreter.NewBool(leftValue.Value.(float64) > rightValue.Value.(float64)), nil
-	} else if leftValue.IsInstanceOf(TString) && rightValue.IsInstanceOf(TString) {
-		return interpreter.NewBool(leftValue.Value.(string) > rightValue.Value.(string)), nil
-	} else if leftValue.Type == rightValue.Type {
-		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v cannot be compared with >", rightValue.Type), tree.Line, tree.Col)
-	}
-	return nil, exception.New(exception.TypeError, "attempted to compare incomparable types with >", tree.Line, tree.Col)
+	return interpreter.compare(">", leftValue, rightValue, tree.Line, tree.Col)
 }
 
-func (interpreter *Interpreter) doLessThanOrEqualTo(tree *parser.Token) (*OtterValue, error) {
-	equal, err := interpreter.doEqualityCheck(tree)
+// Evaluates left < right or left > right to a bool value
+func (interpreter *Interpreter) compare(operator string, left *OtterValue, right *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	result, err := interpreter.compareValues(operator, left, right, line, col)
 	if err != nil {
 		return nil, err
 	}
-	if equal.Value == true {
-		return interpreter.True(), nil
-	}
-	lessThan, err := interpreter.doLessThan(tree)
-	if err != nil {
-		return nil, err
-	}
-	if lessThan.Value == true {
-		return interpreter.True(), nil
-	}
-	return interpreter.False(), nil
+	return interpreter.NewBool(result), nil
+}
+
+func (interpreter *Interpreter) doLessThanOrEqualTo(tree *parser.Token) (*OtterValue, error) {
+	return interpreter.doComparisonOrEqualTo("<", tree)
 }
 
 func (interpreter *Interpreter) doGreaterThanOrEqualTo(tree *parser.Token) (*OtterValue, error) {
-	equal, err := interpreter.doEqualityCheck(tree)
+	return interpreter.doComparisonOrEqualTo(">", tree)
+}
+
+// Evaluates <= or >= as == or the given strict comparison
+func (interpreter *Interpreter) doComparisonOrEqualTo(operator string, tree *parser.Token) (*OtterValue, error) {
+	leftValue, rightValue, err := resolveBinaryOperands(interpreter, tree)
 	if err != nil {
 		return nil, err
 	}
-	if equal.Value == true {
-		return interpreter.True(), nil
-	}
-	lessThan, err := interpreter.doGreaterThan(tree)
+	return interpreter.compareOrEqual(operator, leftValue, rightValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) compareOrEqual(operator string, left *OtterValue, right *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	equal, err := interpreter.valuesEqual(left, right, line, col)
 	if err != nil {
 		return nil, err
 	}
-	if lessThan.Value == true {
+	if equal {
 		return interpreter.True(), nil
 	}
-	return interpreter.False(), nil
+	return interpreter.compare(operator, left, right, line, col)
 }
 
 func (interpreter *Interpreter) doEqualityCheck(tree *parser.Token) (*OtterValue, error) {
@@ -98,36 +81,40 @@ func (interpreter *Interpreter) doEqualityCheck(tree *parser.Token) (*OtterValue
 	if err != nil {
 		return nil, err
 	}
-	areEqual := leftValue.isEqualTo(rightValue)
-	return interpreter.NewBool(areEqual), nil
+	return interpreter.equal(leftValue, rightValue, tree.Line, tree.Col)
 }
 
 func (interpreter *Interpreter) doInequalityCheck(tree *parser.Token) (*OtterValue, error) {
-	result, err := interpreter.doEqualityCheck(tree)
+	leftValue, rightValue, err := resolveBinaryOperands(interpreter, tree)
 	if err != nil {
 		return nil, err
 	}
-	if result.Value == false {
-		return interpreter.True(), nil
-	} else {
-		return interpreter.False(), nil
-	}
+	return interpreter.notEqual(leftValue, rightValue, tree.Line, tree.Col)
 }
 
-func (interpreter *Interpreter) doAnd(tree *parser.Token) (*OtterValue, error) {
-
-	leftValue, rightValue, err := resolveBinaryOperands(interpreter, tree)
-
+// Evaluates left == right to a bool value
+func (interpreter *Interpreter) equal(left *OtterValue, right *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	areEqual, err := interpreter.valuesEqual(left, right, line, col)
 	if err != nil {
 		return nil, err
 	}
-	leftTruthy := interpreter.Truthiness(leftValue)
+	return interpreter.NewBool(areEqual), nil
+}
 
-	if leftTruthy.Value == false {
-		return leftValue, nil
+func (interpreter *Interpreter) notEqual(left *OtterValue, right *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	areEqual, err := interpreter.valuesEqual(left, right, line, col)
+	if err != nil {
+		return nil, err
 	}
+	return interpreter.NewBool(!areEqual), nil
+}
 
-	return rightValue, nil
+func (interpreter *Interpreter) doAnd(tree *parser.Token) (*OtterValue, error) {
+	leftValue, rightValue, err := resolveBinaryOperands(interpreter, tree)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.and(leftValue, rightValue), nil
 }
 
 func (interpreter *Interpreter) doOr(tree *parser.Token) (*OtterValue, error) {
@@ -135,34 +122,57 @@ func (interpreter *Interpreter) doOr(tree *parser.Token) (*OtterValue, error) {
 	if err != nil {
 		return nil, err
 	}
-	leftTruthy := interpreter.Truthiness(leftValue)
-	if leftTruthy.Value == true {
-		return leftValue, nil
+	return interpreter.or(leftValue, rightValue), nil
+}
+
+// Both operands of && and || are always evaluated. && results in
+// the left operand if it is falsy and || if it is truthy, and
+// otherwise both result in the right operand
+func (interpreter *Interpreter) and(left *OtterValue, right *OtterValue) *OtterValue {
+	if interpreter.Truthiness(left).Value == false {
+		return left
+	}
+	return right
+}
+
+func (interpreter *Interpreter) or(left *OtterValue, right *OtterValue) *OtterValue {
+	if interpreter.Truthiness(left).Value == true {
+		return left
 	}
-	return rightValue, nil
+	return right
 }
 
 func (interpreter *Interpreter) doAssigment(tree *parser.Token) (*OtterValue, error) {
 	if len(tree.Children) != 2 {
 		// TODO make more detailed
-		return nil, fmt.Errorf("syntaxerror: invald assignment expression at line %v, col %v", tree.Line, tree.Col)
+		return nil, exception.New(exception.SyntaxError, "invalid assignment expression", tree.Line, tree.Col)
 	}
 	left := tree.Children[0]
 	right := tree.Children[1]
+	if left.Symbol == parser.Index {
+		return interpreter.doIndexAssignment(left, right)
+	}
+	if left.Symbol == parser.Access && left.Children[1].Symbol == parser.Name {
+		return interpreter.doFieldAssignment(left, right)
+	}
 	if left.Symbol != parser.Name {
-		return nil, exception.New(exception.SyntaxError, "invalid assigment expression at line %v, col %v", tree.Line, tree.Col)
+		return nil, exception.New(exception.SyntaxError, "invalid assignment expression", tree.Line, tree.Col)
 	}
 	rightValue, err := interpreter.Evaluate(right)
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.assignVariable(left, rightValue, tree.Line, tree.Col)
+}
 
-	// TODO - handle colisions with builtins
-	err = interpreter.CallStack.AssignVariable(left.Value, rightValue)
+// Assigns a value to the variable name, raising any error at line
+// and col
+func (interpreter *Interpreter) assignVariable(name *parser.Token, value *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	err := interpreter.storeVariable(interpreter.resolution.variables[name], value, line, col)
 	if err != nil {
 		return nil, err
 	}
-	return rightValue, nil
+	return value, nil
 }
 
 func (interpreter *Interpreter) doAddition(tree *parser.Token) (*OtterValue, error) {
@@ -170,6 +180,10 @@ func (interpreter *Interpreter) doAddition(tree *parser.Token) (*OtterValue, err
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.add(leftValue, rightValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) add(leftValue *OtterValue, rightValue *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
 		newValue := leftValue.Value.(int64) + rightValue.Value.(int64)
 		return interpreter.NewInt(newValue), nil
@@ -182,10 +196,34 @@ func (interpreter *Interpreter) doAddition(tree *parser.Token) (*OtterValue, err
 		newValue := leftValue.Value.(string) + rightValue.Value.(string)
 		return interpreter.NewString(newValue), nil
 	}
+	if result, found, err := interpreter.callOperatorMethod(AddMethodName, leftValue, []*OtterValue{rightValue}, line, col); found {
+		return result, err
+	}
 	if leftValue.Type == rightValue.Type {
-		return nil, fmt.Errorf("typeerror: type %v does not support operator + at line %v, col %v", leftValue.Type, tree.Line, tree.Col)
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v does not support operator +", leftValue.Type.Value), line, col)
+	}
+	return nil, exception.New(exception.TypeError, fmt.Sprintf("incompatible types %v and %v with operator +", leftValue.Type.Value, rightValue.Type.Value), line, col)
+}
+
+func (interpreter *Interpreter) doNegation(tree *parser.Token) (*OtterValue, error) {
+	value, err := interpreter.Evaluate(tree.Children[0])
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("typerror: incompatable types %v and %v with operator + at line %v, col %v", leftValue.Type, rightValue.Type, tree.Line, tree.Col)
+	return interpreter.negate(value, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) negate(value *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	if value.IsInstanceOf(TInt) {
+		return interpreter.NewInt(-value.Value.(int64)), nil
+	}
+	if value.IsInstanceOf(TFloat) {
+		return interpreter.NewFloat(-value.Value.(float64)), nil
+	}
+	if result, found, err := interpreter.callOperatorMethod(NegateMethodName, value, []*OtterValue{}, line, col); found {
+		return result, err
+	}
+	return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v does not support unary operator -", value.Type.Value), line, col)
 }
 
 func (interpreter *Interpreter) doSubtraction(tree *parser.Token) (*OtterValue, error) {
@@ -193,6 +231,10 @@ func (interpreter *Interpreter) doSubtraction(tree *parser.Token) (*OtterValue,
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.subtract(leftValue, rightValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) subtract(leftValue *OtterValue, rightValue *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
 		newValue := leftValue.Value.(int64) - rightValue.Value.(int64)
 		return interpreter.NewInt(newValue), nil
@@ -201,10 +243,13 @@ func (interpreter *Interpreter) doSubtraction(tree *parser.Token) (*OtterValue,
 		newValue := leftValue.Value.(float64) - rightValue.Value.(float64)
 		return interpreter.NewFloat(newValue), nil
 	}
+	if result, found, err := interpreter.callOperatorMethod(SubMethodName, leftValue, []*OtterValue{rightValue}, line, col); found {
+		return result, err
+	}
 	if leftValue.Type == rightValue.Type {
-		return nil, fmt.Errorf("typeerror: type %v does not support operator - at line %v, col %v", leftValue.Type, tree.Line, tree.Col)
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v does not support operator -", leftValue.Type.Value), line, col)
 	}
-	return nil, fmt.Errorf("typerror: incompatable types %v and %v with operator - at line %v, col %v", leftValue.Type, rightValue.Type, tree.Line, tree.Col)
+	return nil, exception.New(exception.TypeError, fmt.Sprintf("incompatible types %v and %v with operator -", leftValue.Type.Value, rightValue.Type.Value), line, col)
 }
 
 func (interpreter *Interpreter) doMultiplication(tree *parser.Token) (*OtterValue, error) {
@@ -212,6 +257,10 @@ func (interpreter *Interpreter) doMultiplication(tree *parser.Token) (*OtterValu
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.multiply(leftValue, rightValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) multiply(leftValue *OtterValue, rightValue *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
 		newValue := leftValue.Value.(int64) * rightValue.Value.(int64)
 		return interpreter.NewInt(newValue), nil
@@ -220,10 +269,13 @@ func (interpreter *Interpreter) doMultiplication(tree *parser.Token) (*OtterValu
 		newValue := leftValue.Value.(float64) * rightValue.Value.(float64)
 		return interpreter.NewFloat(newValue), nil
 	}
+	if result, found, err := interpreter.callOperatorMethod(MulMethodName, leftValue, []*OtterValue{rightValue}, line, col); found {
+		return result, err
+	}
 	if leftValue.Type == rightValue.Type {
-		return nil, fmt.Errorf("typeerror: type %v does not support operator * at line %v, col %v", leftValue.Type, tree.Line, tree.Col)
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v does not support operator *", leftValue.Type.Value), line, col)
 	}
-	return nil, fmt.Errorf("typerror: incompatable types %v and %v with operator * at line %v, col %v", leftValue.Type, rightValue.Type, tree.Line, tree.Col)
+	return nil, exception.New(exception.TypeError, fmt.Sprintf("incompatible types %v and %v with operator *", leftValue.Type.Value, rightValue.Type.Value), line, col)
 }
 
 func (interpreter *Interpreter) doDivision(tree *parser.Token) (*OtterValue, error) {
@@ -231,24 +283,31 @@ func (interpreter *Interpreter) doDivision(tree *parser.Token) (*OtterValue, err
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.divide(leftValue, rightValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) divide(leftValue *OtterValue, rightValue *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
 		if rightValue.Value.(int64) == 0 {
-			return nil, fmt.Errorf("dividebyzeroerror: integer division by zero at line %v, col %v", tree.Line, tree.Col)
+			return nil, exception.New(exception.DivideByZeroError, "integer division by zero", line, col)
 		}
 		newValue := leftValue.Value.(int64) / rightValue.Value.(int64)
 		return interpreter.NewInt(newValue), nil
 	}
 	if leftValue.IsInstanceOf(TFloat) && rightValue.IsInstanceOf(TFloat) {
-		if rightValue.Value.(int64) == 0.0 {
-			return nil, fmt.Errorf("dividebyzeroerror: float division by zero at line %v, col %v", tree.Line, tree.Col)
+		if rightValue.Value.(float64) == 0.0 {
+			return nil, exception.New(exception.DivideByZeroError, "float division by zero", line, col)
 		}
 		newValue := leftValue.Value.(float64) / rightValue.Value.(float64)
 		return interpreter.NewFloat(newValue), nil
 	}
+	if result, found, err := interpreter.callOperatorMethod(DivMethodName, leftValue, []*OtterValue{rightValue}, line, col); found {
+		return result, err
+	}
 	if leftValue.Type == rightValue.Type {
-		return nil, fmt.Errorf("typeerror: type %v does not support operator / at line %v, col %v", leftValue.Type, tree.Line, tree.Col)
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v does not support operator /", leftValue.Type.Value), line, col)
 	}
-	return nil, fmt.Errorf("typerror: incompatable types %v and %v with operator / at line %v, col %v", leftValue.Type, rightValue.Type, tree.Line, tree.Col)
+	return nil, exception.New(exception.TypeError, fmt.Sprintf("incompatible types %v and %v with operator /", leftValue.Type.Value, rightValue.Type.Value), line, col)
 }
 
 func (interpreter *Interpreter) doModulo(tree *parser.Token) (*OtterValue, error) {
@@ -256,15 +315,22 @@ func (interpreter *Interpreter) doModulo(tree *parser.Token) (*OtterValue, error
 	if err != nil {
 		return nil, err
 	}
+	return interpreter.modulo(leftValue, rightValue, tree.Line, tree.Col)
+}
+
+func (interpreter *Interpreter) modulo(leftValue *OtterValue, rightValue *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
 	if leftValue.IsInstanceOf(TInt) && rightValue.IsInstanceOf(TInt) {
 		if rightValue.Value.(int64) == 0 {
-			return nil, fmt.Errorf("dividebyzeroerror: integer modulo by zero at line %v, col %v", tree.Line, tree.Col)
+			return nil, exception.New(exception.DivideByZeroError, "integer modulo by zero", line, col)
 		}
 		newValue := leftValue.Value.(int64) % rightValue.Value.(int64)
 		return interpreter.NewInt(newValue), nil
 	}
+	if result, found, err := interpreter.callOperatorMethod(ModMethodName, leftValue, []*OtterValue{rightValue}, line, col); found {
+		return result, err
+	}
 	if leftValue.Type == rightValue.Type {
-		return nil, fmt.Errorf("typeerror: type %v does not support operator %% at line %v, col %v", leftValue.Type, tree.Line, tree.Col)
+		return nil, exception.New(exception.TypeError, fmt.Sprintf("type %v does not support operator %%", leftValue.Type.Value), line, col)
 	}
-	return nil, fmt.Errorf("typerror: incompatable types %v and %v with operator %% at line %v, col %v", leftValue.Type, rightValue.Type, tree.Line, tree.Col)
+	return nil, exception.New(exception.TypeError, fmt.Sprintf("incompatible types %v and %v with operator %%", leftValue.Type.Value, rightValue.Type.Value), line, col)
 }
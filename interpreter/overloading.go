@@ -0,0 +1,107 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Operators that have no builtin meaning for their operands call a
+// method on the type of the left operand instead, so classes and
+// builtin collections can support them. a + b calls a.add(b), -a
+// calls a.negate() and so on. a == b calls a.equals(b) whenever the
+// method exists, and a != b is its opposite. a <= b and a >= b are
+// true if a == b, or a < b or a > b respectively.
+const (
+	AddMethodName         = "add"
+	SubMethodName         = "sub"
+	MulMethodName         = "mul"
+	DivMethodName         = "div"
+	ModMethodName         = "mod"
+	NegateMethodName      = "negate"
+	LessThanMethodName    = "lessThan"
+	GreaterThanMethodName = "greaterThan"
+	EqualsMethodName      = "equals"
+)
+
+// Calls the method overloading an operator on the left operand's type.
+// found is false if the type has no such method
+func (interpreter *Interpreter) callOperatorMethod(methodName string, left *OtterValue, arguments []*OtterValue, line int, col int) (result *OtterValue, found bool, err exception.Exception) {
+	if _, found := findMethod(left.Type, methodName); !found {
+		return nil, false, nil
+	}
+	result, err = interpreter.callMethod(left, methodName, arguments, line, col)
+	return result, true, err
+}
+
+// Tests if two values are equal with ==. Values are always equal to
+// themselves. Otherwise the left value's equals method decides, and
+// values without one are compared structurally
+func (interpreter *Interpreter) valuesEqual(left *OtterValue, right *OtterValue, line int, col int) (equal bool, err exception.Exception) {
+	// Comparing collections can look up keys in hashed collections
+	defer recoverKeyException(&err)
+	checker := interpreter.equalityChecker
+	// Equals methods can compare values with == themselves
+	outerLine, outerCol := checker.line, checker.col
+	checker.line, checker.col = line, col
+	defer func() {
+		checker.line, checker.col = outerLine, outerCol
+	}()
+	equal, err = checker.equal(left, right)
+	if err != nil {
+		return false, locateException(err, line, col)
+	}
+	return equal, nil
+}
+
+// Evaluates left < right or left > right. Ints, floats and strings
+// compare directly, and other values with their lessThan and
+// greaterThan methods
+func (interpreter *Interpreter) compareValues(operator string, left *OtterValue, right *OtterValue, line int, col int) (bool, exception.Exception) {
+	if comparison, err := comparePrimitives(left, right); err == nil {
+		if operator == "<" {
+			return comparison < 0, nil
+		}
+		return comparison > 0, nil
+	}
+	methodName := LessThanMethodName
+	if operator == ">" {
+		methodName = GreaterThanMethodName
+	}
+	result, found, err := interpreter.callOperatorMethod(methodName, left, []*OtterValue{right}, line, col)
+	if err != nil {
+		return false, err
+	}
+	if found {
+		return interpreter.Truthiness(result).Value == true, nil
+	}
+	if left.Type == right.Type {
+		return false, exception.New(exception.TypeError, fmt.Sprintf("type %v cannot be compared with %v", right.Type.Value, operator), line, col)
+	}
+	return false, exception.New(exception.TypeError, fmt.Sprintf("attempted to compare incomparable types with %v", operator), line, col)
+}
+
+// Compares two sequences lexicographically, returning a negative
+// number, zero or a positive number like comparePrimitives. The
+// first elements that aren't equal decide, and otherwise the
+// shorter sequence is the lesser
+func (interpreter *Interpreter) compareSequences(left []*OtterValue, right []*OtterValue) (int, exception.Exception) {
+	for i := 0; i < len(left) && i < len(right); i++ {
+		equal, err := interpreter.valuesEqual(left[i], right[i], 0, 0)
+		if err != nil {
+			return 0, err
+		}
+		if equal {
+			continue
+		}
+		lessThan, err := interpreter.compareValues("<", left[i], right[i], 0, 0)
+		if err != nil {
+			return 0, err
+		}
+		if lessThan {
+			return -1, nil
+		}
+		return 1, nil
+	}
+	return len(left) - len(right), nil
+}
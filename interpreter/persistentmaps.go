@@ -0,0 +1,199 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// PersistentMaps are immutable dictionaries backed by collections.HashMap.
+// Methods that change a map return a new map and leave the original
+// unchanged. Unlike Maps, PersistentMaps accept keys of any type, and
+// don't remember insertion order
+
+func (interpreter *Interpreter) NewPersistentMap(persistentMap collections.Map) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TPersistentMap),
+		Value: persistentMap,
+	}
+}
+
+// Constructs a map from alternating keys and values
+func ConstructPersistentMap(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values)%2 != 0 {
+		return nil, exception.New(exception.ArgumentError, "PersistentMap takes alternating keys and values", 0, 0)
+	}
+	var persistentMap collections.Map = collections.EmptyHashMap()
+	for i := 0; i < len(values); i += 2 {
+		persistentMap = persistentMap.Set(values[i], values[i+1])
+	}
+	return interpreter.NewPersistentMap(persistentMap), nil
+}
+
+func PersistentMapLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewInt(int64(persistentMap.(collections.FiniteIterable).Size())), nil
+}
+
+func PersistentMapGetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	value, found := persistentMap.Get(values[1])
+	if !found {
+		asString, _ := ConstructString(interpreter, []*OtterValue{values[1]})
+		return nil, exception.New(exception.KeyError, fmt.Sprintf("key %v not found", asString.Value), 0, 0)
+	}
+	return value.(*OtterValue), nil
+}
+
+func PersistentMapSetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return nil, exception.New(exception.TypeError, "PersistentMaps are immutable, use set to get an updated copy", 0, 0)
+}
+
+// Returns the value for a key, or a default if the key is not
+// present. The default is null if not given
+func PersistentMapGet(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values) < 2 || len(values) > 3 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("get takes 1 or 2 arguments, got %v", len(values)-1), 0, 0)
+	}
+	persistentMap := values[0].Value.(collections.Map)
+	value, found := persistentMap.Get(values[1])
+	if found {
+		return value.(*OtterValue), nil
+	}
+	if len(values) == 3 {
+		return values[2], nil
+	}
+	return interpreter.NewNull(), nil
+}
+
+func PersistentMapContains(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewBool(persistentMap.Contains(values[1])), nil
+}
+
+// Returns a new map with the key set to the value
+func PersistentMapSet(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewPersistentMap(persistentMap.Set(values[1], values[2])), nil
+}
+
+// Returns a new map without the key
+func PersistentMapRemove(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewPersistentMap(persistentMap.Remove(values[1])), nil
+}
+
+// Returns a new map with the entries of both maps. Where both maps
+// have a key, the value from the other map is used
+func PersistentMapMerge(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	other := values[1]
+	if !other.IsInstanceOf(TPersistentMap) {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("merge expects a PersistentMap, got %v", other.Type.Value), 0, 0)
+	}
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewPersistentMap(persistentMap.Merge(other.Value.(collections.Map))), nil
+}
+
+func PersistentMapKeys(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewSet(persistentMap.KeySet()), nil
+}
+
+func PersistentMapValues(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return ConstructVector(interpreter, toOtterValues(persistentMap.Values().ToSlice()))
+}
+
+// Returns a new map with the same keys, where each value is replaced
+// by the result of calling the function with the key and value
+func PersistentMapMap(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[1], "map")
+	if err != nil {
+		return nil, err
+	}
+	persistentMap := values[0].Value.(collections.Map)
+	var mapped collections.Map = collections.EmptyHashMap()
+	persistentMap.ForEach(func(item interface{}) {
+		entry := item.(collections.MapEntry)
+		mapped = mapped.Set(entry.Key, adapter.call(entry.Key.(*OtterValue), entry.Value.(*OtterValue)))
+	})
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return interpreter.NewPersistentMap(mapped), nil
+}
+
+// Returns a new map with only the entries for which the function,
+// called with the key and value, returns a truthy value
+func PersistentMapFilter(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[1], "filter")
+	if err != nil {
+		return nil, err
+	}
+	persistentMap := values[0].Value.(collections.Map)
+	filtered := persistentMap
+	persistentMap.ForEach(func(item interface{}) {
+		entry := item.(collections.MapEntry)
+		keep := adapter.call(entry.Key.(*OtterValue), entry.Value.(*OtterValue))
+		if interpreter.Truthiness(keep).Value == false {
+			filtered = filtered.Remove(entry.Key)
+		}
+	})
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return interpreter.NewPersistentMap(filtered), nil
+}
+
+// Combines the entries of the map. The function is called with
+// the result so far, a key and its value
+func PersistentMapFold(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[2], "fold")
+	if err != nil {
+		return nil, err
+	}
+	persistentMap := values[0].Value.(collections.Map)
+	result := persistentMap.Fold(values[1], func(accumulator interface{}, item interface{}) interface{} {
+		entry := item.(collections.MapEntry)
+		return adapter.call(accumulator.(*OtterValue), entry.Key.(*OtterValue), entry.Value.(*OtterValue))
+	})
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return result.(*OtterValue), nil
+}
+
+// Iterates over the keys of the map
+func PersistentMapIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	return interpreter.NewCollectionIterator(persistentMap.Keys().Iterator()), nil
+}
+
+// Iterates over [key, value] arrays
+func PersistentMapEntryIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	persistentMap := values[0].Value.(collections.Map)
+	entries := persistentMap.Map(func(item interface{}) interface{} {
+		entry := item.(collections.MapEntry)
+		return interpreter.NewArray([]*OtterValue{entry.Key.(*OtterValue), entry.Value.(*OtterValue)})
+	})
+	return interpreter.NewCollectionIterator(entries.Iterator()), nil
+}
+
+func DefinePersistentMapType(interpreter *Interpreter) {
+	interpreter.DefineType(TPersistentMap, NewBuiltInConstructor(TPersistentMap, Variadic, ConstructPersistentMap))
+	interpreter.DefineBuiltinMethod(TPersistentMap, "length", 1, PersistentMapLength)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "getItem", 2, PersistentMapGetItem)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "setItem", 3, PersistentMapSetItem)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "get", Variadic, PersistentMapGet)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "contains", 2, PersistentMapContains)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "set", 3, PersistentMapSet)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "remove", 2, PersistentMapRemove)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "merge", 2, PersistentMapMerge)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "keys", 1, PersistentMapKeys)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "values", 1, PersistentMapValues)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "map", 2, PersistentMapMap)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "filter", 2, PersistentMapFilter)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "fold", 3, PersistentMapFold)
+	interpreter.DefineBuiltinMethod(TPersistentMap, "iterator", 1, PersistentMapIterator)
+}
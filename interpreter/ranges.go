@@ -0,0 +1,128 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Ranges are immutable sequences of evenly spaced ints backed by
+// collections.Range. Elements are computed as they are needed, so
+// a range takes the same space however many elements it has
+
+func (interpreter *Interpreter) NewRange(r *collections.Range) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TRange),
+		Value: r,
+	}
+}
+
+// Builds a range from Range style arguments: (stop), (start, stop)
+// or (start, stop, step). The start defaults to 0 and the step to 1
+func newRangeFromArguments(name string, values []*OtterValue, inclusive bool) (*collections.Range, exception.Exception) {
+	if len(values) < 1 || len(values) > 3 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v takes 1 to 3 arguments, got %v", name, len(values)), 0, 0)
+	}
+	bounds := []int{0, 0, 1}
+	for i, value := range values {
+		if !value.IsInstanceOf(TInt) {
+			return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v arguments must be int, got %v", name, value.Type.Value), 0, 0)
+		}
+		bounds[i] = int(value.Value.(int64))
+	}
+	if len(values) == 1 {
+		bounds[0], bounds[1] = 0, bounds[0]
+	}
+	start, stop, step := bounds[0], bounds[1], bounds[2]
+	if step == 0 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v step cannot be 0", name), 0, 0)
+	}
+	if inclusive {
+		return collections.NewInclusiveRange(start, stop, step), nil
+	}
+	return collections.NewRange(start, stop, step), nil
+}
+
+// Constructs the range of ints counting from start towards, but not
+// including, stop
+func ConstructRange(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r, err := newRangeFromArguments("Range", values, false)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewRange(r), nil
+}
+
+// Like Range, but includes stop if the range reaches it
+func InclusiveRange(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r, err := newRangeFromArguments("inclusiveRange", values, true)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewRange(r), nil
+}
+
+// Shows a range as a call to Range that constructs an equal range
+func rangeString(r *collections.Range) string {
+	stop := r.Start() + r.Size()*r.Step()
+	return fmt.Sprintf("%v(%v, %v, %v)", TRange, r.Start(), stop, r.Step())
+}
+
+func RangeLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r := values[0].Value.(*collections.Range)
+	return interpreter.NewInt(int64(r.Size())), nil
+}
+
+func RangeGetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r := values[0].Value.(*collections.Range)
+	index, err := normalizeIndex(values[1], r.Size())
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewInt(int64(r.Get(index).(int))), nil
+}
+
+func RangeSetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return nil, exception.New(exception.TypeError, "Ranges are immutable", 0, 0)
+}
+
+// Checks membership arithmetically, without visiting the elements
+func RangeContains(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r := values[0].Value.(*collections.Range)
+	value := values[1]
+	if !value.IsInstanceOf(TInt) {
+		return interpreter.False(), nil
+	}
+	return interpreter.NewBool(r.Contains(int(value.Value.(int64)))), nil
+}
+
+func RangeIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r := values[0].Value.(*collections.Range)
+	elements := r.Map(func(element interface{}) interface{} {
+		return interpreter.NewInt(int64(element.(int)))
+	})
+	return interpreter.NewCollectionIterator(elements.Iterator()), nil
+}
+
+func RangeToArray(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	r := values[0].Value.(*collections.Range)
+	elements := make([]*OtterValue, r.Size())
+	for i := range elements {
+		elements[i] = interpreter.NewInt(int64(r.Get(i).(int)))
+	}
+	return interpreter.NewArray(elements), nil
+}
+
+func DefineRangeType(interpreter *Interpreter) {
+	interpreter.DefineType(TRange, NewBuiltInConstructor(TRange, Variadic, ConstructRange))
+	interpreter.DefineBuiltinMethod(TRange, "length", 1, RangeLength)
+	interpreter.DefineBuiltinMethod(TRange, "getItem", 2, RangeGetItem)
+	interpreter.DefineBuiltinMethod(TRange, "setItem", 3, RangeSetItem)
+	interpreter.DefineBuiltinMethod(TRange, "contains", 2, RangeContains)
+	interpreter.DefineBuiltinMethod(TRange, "iterator", 1, RangeIterator)
+	interpreter.DefineBuiltinMethod(TRange, "toArray", 1, RangeToArray)
+
+	inclusiveRangeFn, _ := interpreter.NewBuiltInFunction("inclusiveRange", Variadic, InclusiveRange)
+	interpreter.DefineGlobal("inclusiveRange", inclusiveRangeFn)
+}
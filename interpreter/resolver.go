@@ -0,0 +1,518 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// The resolver works out what every name in a program refers to before
+// the program runs, so neither the tree walking interpreter nor the
+// virtual machine looks variables up by name. Each local variable gets
+// a slot in the environment of the function or block that declares it,
+// and each global the binding that holds it. Names that don't refer to
+// any variable are NameErrors before anything runs. In the REPL, names
+// in functions can also refer to globals that later input defines.
+//
+// Variables declared with let, const, def, class or import belong to
+// the block they are declared in, and a name only refers to them once
+// their declaration has run. Functions nested in the block can refer
+// to them wherever they are declared, since a function can be called
+// after the declarations that follow it.
+//
+// A name assigned without being declared belongs to the function it is
+// assigned in, or to the global scope outside of functions, unless the
+// function is nested in a scope that already has a variable with that
+// name. Enclosing functions' variables count wherever they are
+// declared, but globals only count if they are declared before the
+// function, so functions don't share variables with globals that
+// happen to have the same name.
+
+// A variable is where the value a name refers to is kept
+type variable struct {
+	name string
+	// The binding of a global variable, or nil for a local one
+	global *Binding
+	// How many environments out from the current one a local
+	// variable's environment is, and its slot there
+	depth int
+	slot  int
+	// For names being assigned, whether they refer to a constant.
+	// For declarations, whether they declare a constant again
+	constant bool
+}
+
+func (v *variable) String() string {
+	if v.global != nil {
+		return fmt.Sprintf("%v global", v.name)
+	}
+	if v.depth == 0 {
+		return fmt.Sprintf("%v local %v", v.name, v.slot)
+	}
+	return fmt.Sprintf("%v local %v up %v", v.name, v.slot, v.depth)
+}
+
+// The local variables of a function call or block, in slot order
+type layout struct {
+	names []string
+}
+
+// What the resolver has worked out about the code run so far
+type resolution struct {
+	// The variable each name refers to. A super name refers to the
+	// instance the method it is in was called on, and a super access
+	// to the class the method belongs to
+	variables map[*parser.Token]*variable
+	// The local variables of each function, block and catch clause
+	layouts map[*parser.Token]*layout
+}
+
+func newResolution() *resolution {
+	return &resolution{
+		variables: map[*parser.Token]*variable{},
+		layouts:   map[*parser.Token]*layout{},
+	}
+}
+
+// The variables of a function, block or catch clause, or of the
+// global scope, as the resolver passes through it
+type resolverScope struct {
+	global   bool
+	function bool
+	layout   *layout
+	slots    map[string]int
+	// Names declared anywhere in the scope with let, const, def,
+	// class or import, and the ones declared with const
+	declarations map[string]bool
+	constants    map[string]bool
+	// Names whose declarations the resolver has passed, and the ones
+	// declared with const
+	declared         map[string]bool
+	declaredConstant map[string]bool
+	// Names assigned without being declared that belong to the scope
+	implicit map[string]bool
+}
+
+func newResolverScope() *resolverScope {
+	return &resolverScope{
+		layout:           &layout{},
+		slots:            map[string]int{},
+		declarations:     map[string]bool{},
+		constants:        map[string]bool{},
+		declared:         map[string]bool{},
+		declaredConstant: map[string]bool{},
+		implicit:         map[string]bool{},
+	}
+}
+
+// Gives a name the next slot, even if it already has one, so
+// parameters with the same name still get a slot each
+func (scope *resolverScope) addSlot(name string) int {
+	scope.slots[name] = len(scope.layout.names)
+	scope.layout.names = append(scope.layout.names, name)
+	return scope.slots[name]
+}
+
+// Declares the names the statements of the scope declare
+func (scope *resolverScope) hoist(statements []*parser.Token) {
+	forEachDeclaration(statements, func(name *parser.Token, constant bool) {
+		if !scope.declarations[name.Value] && !scope.global {
+			scope.addSlot(name.Value)
+		}
+		scope.declarations[name.Value] = true
+		if constant {
+			scope.constants[name.Value] = true
+		}
+	})
+}
+
+// Whether the scope is held in an environment while it runs. Blocks
+// that declare nothing don't need one
+func (scope *resolverScope) hasEnvironment() bool {
+	return scope.function || len(scope.layout.names) > 0
+}
+
+// Finds the slot of a name in a local scope. Names declared in the
+// scope are only visible from code in the same function once their
+// declarations have run, but are visible from nested functions
+// wherever they are declared
+func (scope *resolverScope) slot(name string, nested bool) (int, bool) {
+	index, hasSlot := scope.slots[name]
+	if scope.declarations[name] && (nested || scope.declared[name]) {
+		return index, true
+	}
+	if scope.implicit[name] {
+		if !hasSlot {
+			index = scope.addSlot(name)
+		}
+		return index, true
+	}
+	return 0, false
+}
+
+type resolver struct {
+	interpreter *Interpreter
+	resolution  *resolution
+	globals     *Environment
+	scopes      []*resolverScope
+}
+
+// Resolves the names in statements run in the global scope of the
+// current frame, which is a module's while the module is imported
+func (interpreter *Interpreter) resolve(statements []*parser.Token) exception.Exception {
+	global := newResolverScope()
+	global.global = true
+	global.hoist(statements)
+	if !interpreter.Strict {
+		global.implicit = assignedNames(statements)
+	}
+	r := &resolver{
+		interpreter: interpreter,
+		resolution:  interpreter.resolution,
+		globals:     interpreter.CallStack.Peek().Environment,
+		scopes:      []*resolverScope{global},
+	}
+	return r.resolveAll(statements)
+}
+
+func (r *resolver) current() *resolverScope {
+	return r.scopes[len(r.scopes)-1]
+}
+
+func (r *resolver) push(scope *resolverScope) {
+	r.scopes = append(r.scopes, scope)
+}
+
+func (r *resolver) pop() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *resolver) globalExists(name string) bool {
+	_, found := r.globals.Resolve(name)
+	return found
+}
+
+// Finds the variable a name refers to, and the scope it belongs to.
+// nested is true if the scope is outside the function the name is in
+func (r *resolver) lookup(name string) (v *variable, scope *resolverScope, nested bool) {
+	depth := 0
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		scope = r.scopes[i]
+		if scope.global {
+			visible := scope.implicit[name] || (scope.declarations[name] && (nested || scope.declared[name]))
+			if !visible && !r.globalExists(name) && !(nested && r.interpreter.Incremental) {
+				return nil, nil, false
+			}
+			return &variable{name: name, global: r.globals.binding(name)}, scope, nested
+		}
+		if slot, found := scope.slot(name, nested); found {
+			return &variable{name: name, depth: depth, slot: slot, constant: scope.constants[name]}, scope, nested
+		}
+		if scope.hasEnvironment() {
+			depth++
+		}
+		if scope.function {
+			nested = true
+		}
+	}
+	return nil, nil, false
+}
+
+// Whether a function defined here that assigns to name would assign
+// to an existing variable rather than one of its own
+func (r *resolver) assignsExisting(name string) bool {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		scope := r.scopes[i]
+		if scope.global {
+			return scope.declared[name] || r.globalExists(name)
+		}
+		if scope.declarations[name] || scope.implicit[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *resolver) resolveAll(trees []*parser.Token) exception.Exception {
+	for _, tree := range trees {
+		if err := r.resolveNode(tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *resolver) resolveNode(tree *parser.Token) exception.Exception {
+	switch tree.Symbol {
+	case parser.Name:
+		return r.resolveName(tree)
+	case parser.Assignment:
+		return r.resolveAssignment(tree)
+	case parser.Let, parser.Const:
+		if len(tree.Children) > 1 {
+			if err := r.resolveNode(tree.Children[1]); err != nil {
+				return err
+			}
+		}
+		if len(tree.Children) > 0 {
+			name := tree.Children[0]
+			if r.current().declared[name.Value] {
+				return exception.New(exception.SyntaxError, fmt.Sprintf("%v is already declared in this block", name.Value), name.Line, name.Col)
+			}
+			r.declare(name, tree.Symbol == parser.Const)
+		}
+	case parser.FunctionDefinition:
+		if err := r.resolveFunction(tree, false); err != nil {
+			return err
+		}
+		r.declare(tree.Children[0], false)
+	case parser.FunctionLiteral:
+		return r.resolveFunction(tree, false)
+	case parser.Class:
+		return r.resolveClass(tree)
+	case parser.Import:
+		// The first child is the module's path
+		r.declare(tree.Children[1], false)
+	case parser.FromImport:
+		for _, name := range tree.Children[1:] {
+			r.declare(name, false)
+		}
+	case parser.Block:
+		r.push(r.blockScope(tree, tree.Children))
+		defer r.pop()
+		return r.resolveAll(tree.Children)
+	case parser.Try:
+		return r.resolveTry(tree)
+	case parser.Access:
+		return r.resolveAccess(tree)
+	case parser.While:
+		// A third child is the loop's label
+		return r.resolveAll(tree.Children[:2])
+	case parser.Break, parser.Continue:
+		// Their children are labels
+	default:
+		return r.resolveAll(tree.Children)
+	}
+	return nil
+}
+
+func (r *resolver) resolveName(name *parser.Token) exception.Exception {
+	v, _, _ := r.lookup(name.Value)
+	if v == nil {
+		return exception.New(exception.NameError, fmt.Sprintf("%v is not defined", name.Value), name.Line, name.Col)
+	}
+	r.resolution.variables[name] = v
+	return nil
+}
+
+func (r *resolver) resolveAssignment(tree *parser.Token) exception.Exception {
+	if len(tree.Children) != 2 {
+		return exception.New(exception.SyntaxError, "invalid assignment expression", tree.Line, tree.Col)
+	}
+	if err := r.resolveNode(tree.Children[1]); err != nil {
+		return err
+	}
+	name := tree.Children[0]
+	if name.Symbol != parser.Name {
+		return r.resolveNode(name)
+	}
+	v, scope, nested := r.lookup(name.Value)
+	if v == nil {
+		if r.interpreter.Strict {
+			return exception.New(exception.NameError, fmt.Sprintf("assignment to undeclared variable %v", name.Value), name.Line, name.Col)
+		}
+		return exception.New(exception.NameError, fmt.Sprintf("%v is not defined", name.Value), name.Line, name.Col)
+	}
+	if !nested {
+		scope.declared[name.Value] = true
+	}
+	r.resolution.variables[name] = v
+	return nil
+}
+
+// Resolves the name a declaration in the current scope declares
+func (r *resolver) declare(name *parser.Token, constant bool) {
+	scope := r.current()
+	v := &variable{name: name.Value}
+	if scope.global {
+		v.global = r.globals.binding(name.Value)
+	} else {
+		v.slot = scope.slots[name.Value]
+		v.constant = scope.declaredConstant[name.Value]
+	}
+	scope.declared[name.Value] = true
+	if constant {
+		scope.declaredConstant[name.Value] = true
+	}
+	r.resolution.variables[name] = v
+}
+
+func (r *resolver) blockScope(tree *parser.Token, statements []*parser.Token) *resolverScope {
+	scope := newResolverScope()
+	scope.hoist(statements)
+	r.resolution.layouts[tree] = scope.layout
+	return scope
+}
+
+// Resolves the body of a function definition or literal. Methods
+// also have this and the class they belong to as locals
+func (r *resolver) resolveFunction(tree *parser.Token, method bool) exception.Exception {
+	if err := ValidateFunctionDefinition(tree); err != nil {
+		return err
+	}
+	children := tree.Children
+	if tree.Symbol == parser.FunctionDefinition {
+		children = children[1:]
+	}
+	parameters := children[0].Children
+	body := children[1].Children
+
+	scope := newResolverScope()
+	scope.function = true
+	for _, parameter := range parameters {
+		scope.addSlot(parameter.Value)
+		scope.declarations[parameter.Value] = true
+		scope.declared[parameter.Value] = true
+	}
+	if method {
+		for _, name := range []string{ThisName, classVariableName} {
+			scope.addSlot(name)
+			scope.declarations[name] = true
+			scope.declared[name] = true
+		}
+		scope.constants[ThisName] = true
+	}
+	scope.hoist(body)
+	if !r.interpreter.Strict {
+		for name := range assignedNames(body) {
+			// A name the function also declares shares the
+			// declaration's slot
+			if scope.declarations[name] || !r.assignsExisting(name) {
+				scope.implicit[name] = true
+			}
+		}
+	}
+	r.resolution.layouts[tree] = scope.layout
+
+	r.push(scope)
+	defer r.pop()
+	return r.resolveAll(body)
+}
+
+func (r *resolver) resolveClass(tree *parser.Token) exception.Exception {
+	if len(tree.Children) > 2 {
+		if err := r.resolveName(tree.Children[2]); err != nil {
+			return err
+		}
+	}
+	for _, definition := range tree.Children[1].Children {
+		if err := r.resolveFunction(definition, true); err != nil {
+			return err
+		}
+	}
+	r.declare(tree.Children[0], false)
+	return nil
+}
+
+func (r *resolver) resolveTry(tree *parser.Token) exception.Exception {
+	if len(tree.Children) < 2 {
+		return exception.New(exception.SyntaxError, "invalid try statement", tree.Line, tree.Col)
+	}
+	if err := r.resolveNode(tree.Children[0]); err != nil {
+		return err
+	}
+	for _, clause := range tree.Children[1:] {
+		if clause.Symbol == parser.Finally {
+			if err := r.resolveNode(clause.Children[0]); err != nil {
+				return err
+			}
+			continue
+		}
+		// The caught exception is the only variable of the catch
+		// clause's scope. The exception type isn't a variable
+		scope := newResolverScope()
+		name := clause.Children[0].Value
+		scope.addSlot(name)
+		scope.declarations[name] = true
+		scope.declared[name] = true
+		r.resolution.layouts[clause] = scope.layout
+		r.push(scope)
+		err := r.resolveNode(clause.Children[1])
+		r.pop()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *resolver) resolveAccess(tree *parser.Token) exception.Exception {
+	valueTree := tree.Children[0]
+	targetTree := tree.Children[1]
+	if valueTree.Symbol == parser.Name && valueTree.Value == SuperName {
+		this, _, _ := r.lookup(ThisName)
+		class, _, _ := r.lookup(classVariableName)
+		if this == nil || class == nil {
+			return exception.New(exception.SyntaxError, "super can only be used inside a method", valueTree.Line, valueTree.Col)
+		}
+		r.resolution.variables[valueTree] = this
+		r.resolution.variables[tree] = class
+	} else if err := r.resolveNode(valueTree); err != nil {
+		return err
+	}
+	// The target is a field or method name, but a method call's
+	// arguments are expressions
+	if targetTree.Symbol == parser.FunctionInvocation {
+		return r.resolveAll(targetTree.Children[1:])
+	}
+	return nil
+}
+
+// Calls declare with each name the statements declare in the scope
+// they run in, and whether it is declared with const. Names declared
+// in nested blocks and functions belong to those instead
+func forEachDeclaration(statements []*parser.Token, declare func(name *parser.Token, constant bool)) {
+	for _, tree := range statements {
+		switch tree.Symbol {
+		case parser.Let, parser.Const:
+			if len(tree.Children) > 0 {
+				declare(tree.Children[0], tree.Symbol == parser.Const)
+				forEachDeclaration(tree.Children[1:], declare)
+			}
+		case parser.FunctionDefinition, parser.Class:
+			declare(tree.Children[0], false)
+		case parser.Import:
+			declare(tree.Children[1], false)
+		case parser.FromImport:
+			for _, name := range tree.Children[1:] {
+				declare(name, false)
+			}
+		case parser.FunctionLiteral, parser.Block, parser.Catch, parser.Finally:
+		default:
+			forEachDeclaration(tree.Children, declare)
+		}
+	}
+}
+
+// Returns the names the statements assign to, not counting
+// assignments in nested functions
+func assignedNames(statements []*parser.Token) map[string]bool {
+	names := map[string]bool{}
+	var visit func(trees []*parser.Token)
+	visit = func(trees []*parser.Token) {
+		for _, tree := range trees {
+			switch tree.Symbol {
+			case parser.FunctionDefinition, parser.FunctionLiteral, parser.Class:
+				continue
+			case parser.Assignment:
+				if len(tree.Children) > 0 && tree.Children[0].Symbol == parser.Name {
+					names[tree.Children[0].Value] = true
+				}
+			}
+			visit(tree.Children)
+		}
+	}
+	visit(statements)
+	return names
+}
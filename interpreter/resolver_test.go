@@ -0,0 +1,100 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Runs source on a new engine, with the tree walker or the bytecode
+// compiler, returning the engine and any exception raised
+func runSource(source string, bytecode bool) (*Engine, exception.Exception) {
+	engine := NewEngine()
+	engine.Interpreter.Bytecode = bytecode
+	_, err := engine.Execute(strings.NewReader(source))
+	return engine, err
+}
+
+func TestResolverErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        string
+		exceptionType exception.ExceptionType
+		message       string
+	}{
+		{
+			name:          "super outside a method",
+			source:        "super.speak();",
+			exceptionType: exception.SyntaxError,
+			message:       "super can only be used inside a method",
+		},
+		{
+			name:          "super in a function",
+			source:        "def speak() { super.speak(); }",
+			exceptionType: exception.SyntaxError,
+			message:       "super can only be used inside a method",
+		},
+		{
+			name:          "block variable read after its block",
+			source:        "if (true) { let blockOnly = 1; } blockOnly;",
+			exceptionType: exception.NameError,
+			message:       "blockOnly is not defined",
+		},
+		{
+			name:          "const read after its block",
+			source:        "while (true) { const blockOnly = 1; break; } blockOnly;",
+			exceptionType: exception.NameError,
+			message:       "blockOnly is not defined",
+		},
+		{
+			name:          "let declared twice in a block",
+			source:        "let a = 1; let a = 2;",
+			exceptionType: exception.SyntaxError,
+			message:       "a is already declared in this block",
+		},
+		{
+			name:          "const redeclaring a function",
+			source:        "def f() { let x = 1; if (true) { def g() {} const g = 2; } }",
+			exceptionType: exception.SyntaxError,
+			message:       "g is already declared in this block",
+		},
+		{
+			name:          "undefined name",
+			source:        "undefinedName;",
+			exceptionType: exception.NameError,
+			message:       "undefinedName is not defined",
+		},
+	}
+	for _, test := range tests {
+		for _, bytecode := range []bool{false, true} {
+			// Resolution fails before any of the program runs
+			engine, err := runSource("ran = true; "+test.source, bytecode)
+			if err == nil {
+				t.Fatalf("%v (bytecode %v): expected a %v", test.name, bytecode, test.exceptionType)
+			}
+			otterException, _ := exception.As(err)
+			if otterException.Type != test.exceptionType || otterException.Message != test.message {
+				t.Fatalf("%v (bytecode %v): expected %v: %v, got %v", test.name, bytecode, test.exceptionType, test.message, err)
+			}
+			if _, found := engine.Interpreter.CallStack.Globals().Environment.Resolve("ran"); found {
+				t.Fatalf("%v (bytecode %v): the program ran before the error was reported", test.name, bytecode)
+			}
+		}
+	}
+}
+
+func TestRedeclaringConstantInLaterInput(t *testing.T) {
+	for _, bytecode := range []bool{false, true} {
+		engine, err := runSource("const limit = 1;", bytecode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = engine.Execute(strings.NewReader("x = 0;\nlet limit = 2;"))
+		otterException, _ := exception.As(err)
+		// The error is at the declaration, on the second line
+		if otterException == nil || otterException.Message != "cannot redeclare constant limit" || otterException.Line != 2 || otterException.Col == 0 {
+			t.Fatalf("bytecode %v: expected a TypeError on line 2, got %v", bytecode, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Sets are immutable collections without duplicates, backed by
+// collections.HashSet. Methods that change a set return a new set
+// and leave the original unchanged
+
+func (interpreter *Interpreter) NewSet(set collections.Set) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TSet),
+		Value: set,
+	}
+}
+
+func setArgument(value *OtterValue, methodName string) (collections.Set, exception.Exception) {
+	if !value.IsInstanceOf(TSet) {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("%v expects a Set, got %v", methodName, value.Type.Value), 0, 0)
+	}
+	return value.Value.(collections.Set), nil
+}
+
+func setFromValues(values []*OtterValue) collections.Set {
+	var set collections.Set = collections.EmptyHashSet()
+	for _, value := range values {
+		set = set.Add(value)
+	}
+	return set
+}
+
+func ConstructSet(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return interpreter.NewSet(setFromValues(values)), nil
+}
+
+func SetLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	set := values[0].Value.(collections.Set)
+	return interpreter.NewInt(int64(set.Size())), nil
+}
+
+func SetContains(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	set := values[0].Value.(collections.Set)
+	return interpreter.NewBool(set.Contains(values[1])), nil
+}
+
+// Returns a new set with the given values added
+func SetAdd(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	set := values[0].Value.(collections.Set)
+	for _, value := range values[1:] {
+		set = set.Add(value)
+	}
+	return interpreter.NewSet(set), nil
+}
+
+// Returns a new set with the given value removed
+func SetRemove(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	set := values[0].Value.(collections.Set)
+	return interpreter.NewSet(set.Remove(values[1])), nil
+}
+
+func newSetOperation(methodName string, operation func(collections.Set, collections.Set) collections.Set) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		other, err := setArgument(values[1], methodName)
+		if err != nil {
+			return nil, err
+		}
+		return interpreter.NewSet(operation(values[0].Value.(collections.Set), other)), nil
+	}
+}
+
+func SetSubsetOf(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	other, err := setArgument(values[1], "subsetOf")
+	if err != nil {
+		return nil, err
+	}
+	set := values[0].Value.(collections.Set)
+	return interpreter.NewBool(set.SubsetOf(other)), nil
+}
+
+func SetMap(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[1], "map")
+	if err != nil {
+		return nil, err
+	}
+	set := values[0].Value.(collections.Set)
+	mapped := toOtterValues(set.Map(adapter.mapFn).ToSlice())
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return interpreter.NewSet(setFromValues(mapped)), nil
+}
+
+func SetFilter(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[1], "filter")
+	if err != nil {
+		return nil, err
+	}
+	set := values[0].Value.(collections.Set)
+	filtered := toOtterValues(set.Filter(adapter.filterFn).ToSlice())
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return interpreter.NewSet(setFromValues(filtered)), nil
+}
+
+// Combines the elements of the set. Sets are unordered, so the
+// function should give the same result whatever order it sees
+// the elements in
+func SetFold(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[2], "fold")
+	if err != nil {
+		return nil, err
+	}
+	set := values[0].Value.(collections.Set)
+	result := set.Fold(values[1], adapter.reducerFn)
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return result.(*OtterValue), nil
+}
+
+func SetIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	set := values[0].Value.(collections.Set)
+	return interpreter.NewCollectionIterator(set.Iterator()), nil
+}
+
+func SetToArray(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	set := values[0].Value.(collections.Set)
+	return interpreter.NewArray(toOtterValues(set.ToSlice())), nil
+}
+
+func DefineSetType(interpreter *Interpreter) {
+	interpreter.DefineType(TSet, NewBuiltInConstructor(TSet, Variadic, ConstructSet))
+	interpreter.DefineBuiltinMethod(TSet, "length", 1, SetLength)
+	interpreter.DefineBuiltinMethod(TSet, "contains", 2, SetContains)
+	interpreter.DefineBuiltinMethod(TSet, "add", Variadic, SetAdd)
+	interpreter.DefineBuiltinMethod(TSet, "remove", 2, SetRemove)
+	interpreter.DefineBuiltinMethod(TSet, "union", 2, newSetOperation("union", collections.Set.Union))
+	interpreter.DefineBuiltinMethod(TSet, "intersect", 2, newSetOperation("intersect", collections.Set.Intersect))
+	interpreter.DefineBuiltinMethod(TSet, "difference", 2, newSetOperation("difference", collections.Set.Difference))
+	interpreter.DefineBuiltinMethod(TSet, "subsetOf", 2, SetSubsetOf)
+	interpreter.DefineBuiltinMethod(TSet, "map", 2, SetMap)
+	interpreter.DefineBuiltinMethod(TSet, "filter", 2, SetFilter)
+	interpreter.DefineBuiltinMethod(TSet, "fold", 3, SetFold)
+	interpreter.DefineBuiltinMethod(TSet, "iterator", 1, SetIterator)
+	interpreter.DefineBuiltinMethod(TSet, "toArray", 1, SetToArray)
+}
@@ -0,0 +1,54 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// Statements that jump out of the code enclosing them, like break
+// and return, are evaluated to signals. Signals are passed up through
+// Evaluate like errors, unwinding blocks, loops and ifs until they
+// reach the loop or function call they apply to. They are not
+// exceptions, and can't be caught
+
+// A loopSignal carries a break or continue statement from where
+// it is evaluated out to the loop it applies to
+type loopSignal struct {
+	symbol parser.Symbol
+	// The label of the loop the signal applies to, or "" for the
+	// innermost loop
+	label string
+	line  int
+	col   int
+}
+
+func (signal *loopSignal) Error() string {
+	statement := "break"
+	if signal.symbol == parser.Continue {
+		statement = "continue"
+	}
+	return fmt.Sprintf("%v outside of a loop at %v:%v", statement, signal.line, signal.col)
+}
+
+// A returnSignal carries a return statement's value from where
+// it is evaluated out to the function call it returns from
+type returnSignal struct {
+	value *OtterValue
+	line  int
+	col   int
+}
+
+func (signal *returnSignal) Error() string {
+	return fmt.Sprintf("return outside of a function at %v:%v", signal.line, signal.col)
+}
+
+// Reports whether an error is a control flow signal
+// rather than an exception
+func isControlFlowSignal(err error) bool {
+	switch err.(type) {
+	case *loopSignal, *returnSignal:
+		return true
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/nicholasbailey/otter/collections"
 	"github.com/nicholasbailey/otter/exception"
 )
 
@@ -12,33 +13,97 @@ func ConstructString(interpreter *Interpreter, values []*OtterValue) (*OtterValu
 		// TODO - get call stack info for builtins
 		return nil, exception.New(exception.ArgumentError, "", 0, 0)
 	}
-	value := values[0]
-	var strVal string
-	switch value.Type.Value {
+	strVal := displayString(values[0], map[*OtterValue]bool{})
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TString),
+		Value: strVal,
+	}, nil
+}
+
+// Converts a value to the string shown by string() and print. Arrays
+// and maps are shown in literal syntax. inProgress holds the
+// collections currently being displayed, so a collection that
+// contains itself is shown as [...] or {...} instead of recursing forever
+func displayString(value *OtterValue, inProgress map[*OtterValue]bool) string {
+	switch value.baseType().Value {
 	case TString:
-		strVal = value.Value.(string)
+		return value.Value.(string)
 	case TInt:
 		// TODO - move away from builtin
-		strVal = strconv.FormatInt(value.Value.(int64), 10)
+		return strconv.FormatInt(value.Value.(int64), 10)
 	case TBool:
 		if value.Value == true {
-			strVal = "true"
-		} else {
-			strVal = "false"
+			return "true"
 		}
+		return "false"
 	case TFloat:
-		strVal = strconv.FormatFloat(value.Value.(float64), 'f', -1, 64)
+		return strconv.FormatFloat(value.Value.(float64), 'f', -1, 64)
 	case TNull:
-		strVal = "<null>"
+		return "<null>"
 	case TFunction:
-		strVal = value.Callable.Name
-	default:
-		strVal = "[Object]"
+		return value.Callable.Name
+	case TException:
+		return value.Value.(*exception.OtterException).Error()
+	case TModule:
+		return "<module " + value.Value.(*Module).Name + ">"
+	case TArray:
+		if inProgress[value] {
+			return "[...]"
+		}
+		inProgress[value] = true
+		defer delete(inProgress, value)
+		elements := []string{}
+		for _, element := range value.Value.([]*OtterValue) {
+			elements = append(elements, elementString(element, inProgress))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case TMap:
+		if inProgress[value] {
+			return "{...}"
+		}
+		inProgress[value] = true
+		defer delete(inProgress, value)
+		entries := []string{}
+		for _, entry := range value.Value.(*MapInternals).order {
+			entries = append(entries, elementString(entry.Key, inProgress)+": "+elementString(entry.Value, inProgress))
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	case TVector:
+		return constructorString(TVector, vectorElements(value.Value.(collections.Vector)), inProgress)
+	case TSet:
+		return constructorString(TSet, toOtterValues(value.Value.(collections.Set).ToSlice()), inProgress)
+	case TPersistentMap:
+		keysAndValues := []*OtterValue{}
+		value.Value.(collections.Map).ForEach(func(item interface{}) {
+			entry := item.(collections.MapEntry)
+			keysAndValues = append(keysAndValues, entry.Key.(*OtterValue), entry.Value.(*OtterValue))
+		})
+		return constructorString(TPersistentMap, keysAndValues, inProgress)
+	case TRange:
+		return rangeString(value.Value.(*collections.Range))
 	}
-	return &OtterValue{
-		Type:  interpreter.MustResolveType(TString),
-		Value: strVal,
-	}, nil
+	if value.Fields != nil {
+		return instanceString(value, inProgress)
+	}
+	return "[Object]"
+}
+
+// Shows a collection without literal syntax as a call to its constructor
+func constructorString(typeName TypeName, arguments []*OtterValue, inProgress map[*OtterValue]bool) string {
+	elements := []string{}
+	for _, argument := range arguments {
+		elements = append(elements, elementString(argument, inProgress))
+	}
+	return string(typeName) + "(" + strings.Join(elements, ", ") + ")"
+}
+
+// Converts a collection element to a string. Strings are
+// quoted so that they read as string literals
+func elementString(value *OtterValue, inProgress map[*OtterValue]bool) string {
+	if value.IsInstanceOf(TString) {
+		return strconv.Quote(value.Value.(string))
+	}
+	return displayString(value, inProgress)
 }
 
 func (interpreter *Interpreter) NewString(s string) *OtterValue {
@@ -87,9 +152,11 @@ func StringIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue
 	return ConstructStringIterator(interpreter, values)
 }
 
+// String iterators produce the characters of a string, so they
+// index its runes rather than its bytes
 type StringIteratorInternals struct {
-	String string
-	Index  int
+	Runes []rune
+	Index int
 }
 
 func ConstructStringIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
@@ -102,8 +169,8 @@ func ConstructStringIterator(interpreter *Interpreter, values []*OtterValue) (*O
 		return nil, exception.New(exception.ArgumentError, "argument str to constructor StringIterator must be a string", 0, 0)
 	}
 	iteratorValue := StringIteratorInternals{
-		String: value.Value.(string),
-		Index:  0,
+		Runes: []rune(value.Value.(string)),
+		Index: 0,
 	}
 	return &OtterValue{
 		Type:  interpreter.MustResolveType(TStringIterator),
@@ -114,7 +181,7 @@ func ConstructStringIterator(interpreter *Interpreter, values []*OtterValue) (*O
 func StringIteratorHasNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
 	iterator := values[0]
 	internals := iterator.Value.(*StringIteratorInternals)
-	if internals.Index >= len(internals.String) {
+	if internals.Index >= len(internals.Runes) {
 		return interpreter.False(), nil
 	} else {
 		return interpreter.True(), nil
@@ -124,10 +191,10 @@ func StringIteratorHasNext(interpreter *Interpreter, values []*OtterValue) (*Ott
 func StringIteratorGetNext(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
 	iterator := values[0]
 	internals := iterator.Value.(*StringIteratorInternals)
-	if internals.Index >= len(internals.String) {
+	if internals.Index >= len(internals.Runes) {
 		return nil, exception.New(exception.IterationError, "iterable has no more elements", 0, 0)
 	}
-	value := string([]rune(internals.String)[internals.Index])
+	value := string(internals.Runes[internals.Index])
 	internals.Index = internals.Index + 1
 	return interpreter.NewString(value), nil
 }
@@ -0,0 +1,141 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+func init() {
+	RegisterModule("strings", DefineStringsModule)
+}
+
+// Defines the strings module, which holds functions on strings and
+// Builder, a type that builds a string from pieces
+func DefineStringsModule(module *ModuleBuilder) exception.Exception {
+	module.Function("join", 2, StringsJoin)
+	module.Function("split", 2, StringsSplit)
+	module.Function("trim", 1, StringsTrim)
+	module.Function("repeat", 2, StringsRepeat)
+	module.Function("contains", 2, stringsPredicate("contains", strings.Contains))
+	module.Function("startsWith", 2, stringsPredicate("startsWith", strings.HasPrefix))
+	module.Function("endsWith", 2, stringsPredicate("endsWith", strings.HasSuffix))
+	module.Function("indexOf", 2, StringsIndexOf)
+
+	var builder *OtterValue
+	builder = module.Type("Builder", 0, func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		return &OtterValue{Type: builder, Value: &strings.Builder{}}, nil
+	})
+	module.Method(builder, "append", 2, StringBuilderAppend)
+	module.Method(builder, "length", 1, StringBuilderLength)
+	module.Method(builder, "toString", 1, StringBuilderToString)
+	return nil
+}
+
+func stringArgument(function string, value *OtterValue) (string, exception.Exception) {
+	s, isString := value.Value.(string)
+	if !isString {
+		return "", exception.New(exception.ArgumentError, fmt.Sprintf("%v expects a string, got %v", function, value.Type.Value), 0, 0)
+	}
+	return s, nil
+}
+
+// Joins the elements of an array with a separator. Elements that
+// aren't strings are converted like string() converts them
+func StringsJoin(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	elements, isArray := values[0].Value.([]*OtterValue)
+	if !isArray {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("join expects an Array, got %v", values[0].Type.Value), 0, 0)
+	}
+	separator, err := stringArgument("join", values[1])
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{}
+	for _, element := range elements {
+		parts = append(parts, displayString(element, map[*OtterValue]bool{}))
+	}
+	return interpreter.NewString(strings.Join(parts, separator)), nil
+}
+
+func StringsSplit(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	s, err := stringArgument("split", values[0])
+	if err != nil {
+		return nil, err
+	}
+	separator, err := stringArgument("split", values[1])
+	if err != nil {
+		return nil, err
+	}
+	parts := []*OtterValue{}
+	for _, part := range strings.Split(s, separator) {
+		parts = append(parts, interpreter.NewString(part))
+	}
+	return interpreter.NewArray(parts), nil
+}
+
+// Removes whitespace from the start and end of a string
+func StringsTrim(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	s, err := stringArgument("trim", values[0])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewString(strings.TrimSpace(s)), nil
+}
+
+func StringsRepeat(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	s, err := stringArgument("repeat", values[0])
+	if err != nil {
+		return nil, err
+	}
+	count, isInt := values[1].Value.(int64)
+	if !isInt || count < 0 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("repeat expects a count that is an int of at least 0, got %v", displayString(values[1], map[*OtterValue]bool{})), 0, 0)
+	}
+	return interpreter.NewString(strings.Repeat(s, int(count))), nil
+}
+
+// Returns the index of the first occurrence of a substring in a
+// string, or -1 if there is none
+func StringsIndexOf(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	s, err := stringArgument("indexOf", values[0])
+	if err != nil {
+		return nil, err
+	}
+	substring, err := stringArgument("indexOf", values[1])
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewInt(int64(strings.Index(s, substring))), nil
+}
+
+func stringsPredicate(name string, predicate func(s string, substring string) bool) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		s, err := stringArgument(name, values[0])
+		if err != nil {
+			return nil, err
+		}
+		substring, err := stringArgument(name, values[1])
+		if err != nil {
+			return nil, err
+		}
+		return interpreter.NewBool(predicate(s, substring)), nil
+	}
+}
+
+// Adds a value to the end of the string being built, returning the
+// builder so calls can be chained
+func StringBuilderAppend(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	builder := values[0].Value.(*strings.Builder)
+	builder.WriteString(displayString(values[1], map[*OtterValue]bool{}))
+	return values[0], nil
+}
+
+func StringBuilderLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return interpreter.NewInt(int64(values[0].Value.(*strings.Builder).Len())), nil
+}
+
+func StringBuilderToString(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return interpreter.NewString(values[0].Value.(*strings.Builder).String()), nil
+}
@@ -1,6 +1,10 @@
 package interpreter
 
-import "github.com/nicholasbailey/otter/exception"
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
 
 type TypeName string
 
@@ -13,7 +17,20 @@ const (
 	TFunction       TypeName = "function"
 	TType           TypeName = "type"
 	TArray          TypeName = "Array"
+	TArrayIterator  TypeName = "ArrayIterator"
 	TStringIterator TypeName = "StringIterator"
+	TMap            TypeName = "Map"
+	TMapIterator    TypeName = "MapIterator"
+	TVector         TypeName = "Vector"
+	TSet            TypeName = "Set"
+	TPersistentMap  TypeName = "PersistentMap"
+	TRange          TypeName = "Range"
+	// Pairs the elements of another iterator with their indices
+	TIndexedIterator TypeName = "IndexedIterator"
+	// Iterates over a Vector, Set, PersistentMap or Range
+	TCollectionIterator TypeName = "CollectionIterator"
+	TException          TypeName = "Exception"
+	TModule             TypeName = "Module"
 )
 
 func ConstructType(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
@@ -21,9 +38,12 @@ func ConstructType(interpreter *Interpreter, values []*OtterValue) (*OtterValue,
 	return v.Type, nil
 }
 
+// Returns the builtin type with the given name
 func (interpreter *Interpreter) ResolveType(typeName TypeName) (*OtterValue, exception.Exception) {
-	val, _ := interpreter.CallStack.ResolveVariable(string(typeName))
-	// TODO - error handling and make this more efficient
+	val, found := interpreter.types[typeName]
+	if !found {
+		return nil, exception.New(exception.NameError, fmt.Sprintf("%v is not a builtin type", typeName), 0, 0)
+	}
 	return val, nil
 }
 
@@ -35,22 +55,53 @@ func (interpreter *Interpreter) MustResolveType(typeName TypeName) *OtterValue {
 	return val
 }
 
-func (interpreter *Interpreter) DefineType(t TypeName, constructor *Callable) (*OtterValue, exception.Exception) {
-	value := &OtterValue{
+// Creates a type that isn't a builtin, like the types native modules
+// export
+func (interpreter *Interpreter) NewType(t TypeName, constructor *Callable) *OtterValue {
+	return &OtterValue{
 		Type:     interpreter.MustResolveType(TType),
 		Value:    t,
 		Callable: constructor,
 		Methods:  map[string]*Callable{},
 	}
-	err := interpreter.CallStack.AssignVariable(string(t), value)
-	if err != nil {
-		return nil, err
-	}
-	return value, err
 }
 
+func (interpreter *Interpreter) DefineType(t TypeName, constructor *Callable) (*OtterValue, exception.Exception) {
+	value := interpreter.NewType(t, constructor)
+	interpreter.types[t] = value
+	interpreter.DefineGlobal(string(t), value)
+	return value, nil
+}
+
+// Tests if a value's type, or any type it extends, has the given name
 func (value *OtterValue) IsInstanceOf(typeName TypeName) bool {
-	return value.Type.Value == typeName
+	for t := value.Type; t != nil; t = t.Parent {
+		if t.Value == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// Tests if a type is the same as, or extends, another type
+func (t *OtterValue) isSubtypeOf(other *OtterValue) bool {
+	for ; t != nil; t = t.Parent {
+		if t == other {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the type at the top of a value's type hierarchy. For
+// instances of classes that extend a builtin type, this is the
+// builtin type
+func (value *OtterValue) baseType() *OtterValue {
+	t := value.Type
+	for t.Parent != nil {
+		t = t.Parent
+	}
+	return t
 }
 
 // Tests if two objects of type 'type' are equal
@@ -75,5 +126,6 @@ func DefineTypeType(interpreter *Interpreter) {
 
 	typeVal.Type = &typeVal
 
-	interpreter.CallStack.Globals().Scope["type"] = &typeVal
+	interpreter.types[TType] = &typeVal
+	interpreter.DefineGlobal(string(TType), &typeVal)
 }
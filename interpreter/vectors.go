@@ -0,0 +1,232 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/collections"
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Vectors are immutable sequences backed by collections.Vector.
+// Methods that change a vector return a new vector and leave
+// the original unchanged
+
+func (interpreter *Interpreter) NewVector(vector collections.Vector) *OtterValue {
+	return &OtterValue{
+		Type:  interpreter.MustResolveType(TVector),
+		Value: vector,
+	}
+}
+
+func vectorTooLarge() exception.Exception {
+	return exception.New(exception.IndexError, fmt.Sprintf("Vectors can hold at most %v elements", collections.MaxVectorSize), 0, 0)
+}
+
+func vectorAppend(vector collections.Vector, values ...*OtterValue) (collections.Vector, exception.Exception) {
+	if vector.Size()+len(values) > collections.MaxVectorSize {
+		return nil, vectorTooLarge()
+	}
+	for _, value := range values {
+		vector = vector.Append(value)
+	}
+	return vector, nil
+}
+
+func vectorElements(vector collections.Vector) []*OtterValue {
+	return toOtterValues(vector.(collections.Iterable).ToSlice())
+}
+
+func ConstructVector(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector, err := vectorAppend(collections.EmptyVector(), values...)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewVector(vector), nil
+}
+
+func VectorLength(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector := values[0].Value.(collections.Vector)
+	return interpreter.NewInt(int64(vector.Size())), nil
+}
+
+func VectorGetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector := values[0].Value.(collections.Vector)
+	index, err := normalizeIndex(values[1], vector.Size())
+	if err != nil {
+		return nil, err
+	}
+	return vector.Get(index).(*OtterValue), nil
+}
+
+func VectorSetItem(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return nil, exception.New(exception.TypeError, "Vectors are immutable, use update to get an updated copy", 0, 0)
+}
+
+// Returns a new vector with the given values added to the end
+func VectorAppend(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector, err := vectorAppend(values[0].Value.(collections.Vector), values[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewVector(vector), nil
+}
+
+// Returns a new vector with the given values added to the start, in order
+func VectorPrepend(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector := values[0].Value.(collections.Vector)
+	if vector.Size()+len(values)-1 > collections.MaxVectorSize {
+		return nil, vectorTooLarge()
+	}
+	for i := len(values) - 1; i > 0; i-- {
+		vector = vector.Prepend(values[i])
+	}
+	return interpreter.NewVector(vector), nil
+}
+
+// Returns a new vector of the elements from start up to, but not
+// including, end. Bounds work as they do for Array.slice
+func VectorSlice(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	if len(values) < 2 || len(values) > 3 {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("slice takes 1 or 2 arguments, got %v", len(values)-1), 0, 0)
+	}
+	vector := values[0].Value.(collections.Vector)
+	start, err := normalizeSliceBound(values[1], vector.Size())
+	if err != nil {
+		return nil, err
+	}
+	end := vector.Size()
+	if len(values) == 3 {
+		end, err = normalizeSliceBound(values[2], vector.Size())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if end < start {
+		end = start
+	}
+	return interpreter.NewVector(vector.Slice(start, end)), nil
+}
+
+// Builds the < or > method of vectors, which compare elementwise
+func vectorComparison(methodName string, lessThan bool) BuiltInFunction {
+	return func(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+		other := values[1]
+		if !other.IsInstanceOf(TVector) {
+			return nil, exception.New(exception.TypeError, fmt.Sprintf("%v expects a Vector, got %v", methodName, other.Type.Value), 0, 0)
+		}
+		comparison, err := interpreter.compareSequences(vectorElements(values[0].Value.(collections.Vector)), vectorElements(other.Value.(collections.Vector)))
+		if err != nil {
+			return nil, err
+		}
+		if lessThan {
+			return interpreter.NewBool(comparison < 0), nil
+		}
+		return interpreter.NewBool(comparison > 0), nil
+	}
+}
+
+// Returns a new vector of the elements of this vector followed
+// by the elements of another
+func VectorConcat(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	other := values[1]
+	if !other.IsInstanceOf(TVector) {
+		return nil, exception.New(exception.ArgumentError, fmt.Sprintf("concat expects a Vector, got %v", other.Type.Value), 0, 0)
+	}
+	vector := values[0].Value.(collections.Vector)
+	otherVector := other.Value.(collections.Vector)
+	if vector.Size()+otherVector.Size() > collections.MaxVectorSize {
+		return nil, vectorTooLarge()
+	}
+	return interpreter.NewVector(vector.Concat(otherVector)), nil
+}
+
+// Returns a new vector with the element at the given index replaced
+func VectorUpdate(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector := values[0].Value.(collections.Vector)
+	index, err := normalizeIndex(values[1], vector.Size())
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewVector(vector.Update(index, values[2])), nil
+}
+
+func VectorContains(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	vector := values[0].Value.(collections.Vector)
+	for i := 0; i < vector.Size(); i++ {
+		if vector.Get(i).(*OtterValue).Equals(values[1]) {
+			return interpreter.True(), nil
+		}
+	}
+	return interpreter.False(), nil
+}
+
+func VectorMap(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[1], "map")
+	if err != nil {
+		return nil, err
+	}
+	iterable := values[0].Value.(collections.Iterable)
+	mapped := toOtterValues(iterable.Map(adapter.mapFn).ToSlice())
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return ConstructVector(interpreter, mapped)
+}
+
+func VectorFilter(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[1], "filter")
+	if err != nil {
+		return nil, err
+	}
+	iterable := values[0].Value.(collections.Iterable)
+	filtered := toOtterValues(iterable.Filter(adapter.filterFn).ToSlice())
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return ConstructVector(interpreter, filtered)
+}
+
+// Combines the elements of the vector from first to last. The
+// function is called with the result so far and the next element
+func VectorFold(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	adapter, err := interpreter.newCallbackAdapter(values[2], "fold")
+	if err != nil {
+		return nil, err
+	}
+	iterable := values[0].Value.(collections.Iterable)
+	result := iterable.Fold(values[1], adapter.reducerFn)
+	if adapter.err != nil {
+		return nil, adapter.err
+	}
+	return result.(*OtterValue), nil
+}
+
+func VectorIterator(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	iterable := values[0].Value.(collections.Iterable)
+	return interpreter.NewCollectionIterator(iterable.Iterator()), nil
+}
+
+func VectorToArray(interpreter *Interpreter, values []*OtterValue) (*OtterValue, exception.Exception) {
+	return interpreter.NewArray(vectorElements(values[0].Value.(collections.Vector))), nil
+}
+
+func DefineVectorType(interpreter *Interpreter) {
+	interpreter.DefineType(TVector, NewBuiltInConstructor(TVector, Variadic, ConstructVector))
+	interpreter.DefineBuiltinMethod(TVector, "length", 1, VectorLength)
+	interpreter.DefineBuiltinMethod(TVector, "getItem", 2, VectorGetItem)
+	interpreter.DefineBuiltinMethod(TVector, "setItem", 3, VectorSetItem)
+	interpreter.DefineBuiltinMethod(TVector, "append", Variadic, VectorAppend)
+	interpreter.DefineBuiltinMethod(TVector, "prepend", Variadic, VectorPrepend)
+	interpreter.DefineBuiltinMethod(TVector, "slice", Variadic, VectorSlice)
+	interpreter.DefineBuiltinMethod(TVector, "concat", 2, VectorConcat)
+	interpreter.DefineBuiltinMethod(TVector, "update", 3, VectorUpdate)
+	interpreter.DefineBuiltinMethod(TVector, "contains", 2, VectorContains)
+	interpreter.DefineBuiltinMethod(TVector, "map", 2, VectorMap)
+	interpreter.DefineBuiltinMethod(TVector, "filter", 2, VectorFilter)
+	interpreter.DefineBuiltinMethod(TVector, "fold", 3, VectorFold)
+	interpreter.DefineBuiltinMethod(TVector, "iterator", 1, VectorIterator)
+	interpreter.DefineBuiltinMethod(TVector, "toArray", 1, VectorToArray)
+	interpreter.DefineBuiltinMethod(TVector, "add", 2, VectorConcat)
+	interpreter.DefineBuiltinMethod(TVector, "lessThan", 2, vectorComparison("lessThan", true))
+	interpreter.DefineBuiltinMethod(TVector, "greaterThan", 2, vectorComparison("greaterThan", false))
+}
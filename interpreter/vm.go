@@ -0,0 +1,261 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+// A tryHandler records where to continue when an exception is raised
+// inside a try block, and the state to restore before continuing
+type tryHandler struct {
+	target      int
+	depth       int
+	environment *Environment
+}
+
+// Runs a chunk in the current call stack frame, returning the value
+// of its last statement or the value it returns
+func (interpreter *Interpreter) run(chunk *Chunk) (*OtterValue, exception.Exception) {
+	frame := interpreter.CallStack.Peek()
+	environment := frame.Environment
+	stack := make([]*OtterValue, 0, 8)
+	handlers := []tryHandler{}
+	// The exceptions that were being handled before each catch block
+	// that is running started
+	caught := []*exception.OtterException{}
+
+	pop := func() *OtterValue {
+		value := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return value
+	}
+
+	instructions := chunk.Instructions
+	for pc := 0; pc < len(instructions); {
+		instruction := instructions[pc]
+		position := chunk.Positions[pc]
+		line, col := position.Line, position.Col
+		pc++
+
+		var result *OtterValue
+		var err exception.Exception
+		switch instruction.Op {
+		case OpConstant:
+			stack = append(stack, chunk.Constants[instruction.A])
+		case OpNull:
+			stack = append(stack, interpreter.NewNull())
+		case OpPop:
+			stack = stack[:len(stack)-1]
+		case OpDrop:
+			stack = stack[:len(stack)-instruction.A]
+		case OpLoadVariable:
+			result, err = interpreter.loadVariable(chunk.Variables[instruction.A], line, col)
+		case OpStoreVariable:
+			err = interpreter.storeVariable(chunk.Variables[instruction.A], stack[len(stack)-1], line, col)
+		case OpDeclareVariable, OpDeclareConstant:
+			err = interpreter.declareVariable(chunk.Variables[instruction.A], stack[len(stack)-1], instruction.Op == OpDeclareConstant, line, col)
+		case OpAdd, OpSubtract, OpMultiply, OpDivide, OpModulo,
+			OpEqual, OpNotEqual, OpLessThan, OpGreaterThan, OpLessThanOrEqual, OpGreaterThanOrEqual,
+			OpAnd, OpOr, OpInstanceOf:
+			right := pop()
+			left := pop()
+			result, err = interpreter.binaryOperation(instruction.Op, left, right, line, col)
+		case OpNegate:
+			result, err = interpreter.negate(pop(), line, col)
+		case OpJump:
+			pc = instruction.A
+		case OpJumpIfFalse:
+			if interpreter.Truthiness(pop()).Value == false {
+				pc = instruction.A
+			}
+		case OpPushScope:
+			interpreter.CallStack.PushScope(chunk.Layouts[instruction.A].names)
+		case OpPopScope:
+			interpreter.CallStack.PopScope()
+		case OpSetResult:
+			value := pop()
+			stack[len(stack)-1] = value
+		case OpCall:
+			arguments := popArguments(&stack, instruction.A)
+			function := pop()
+			if function.Callable == nil {
+				err = notCallable(chunk.Names[instruction.B], function, line, col)
+				break
+			}
+			result, err = interpreter.invokeCallable(function.Callable, arguments, line, col)
+		case OpGetMember:
+			result, err = interpreter.getMember(pop(), chunk.Names[instruction.A], line, col)
+		case OpCallMember:
+			arguments := popArguments(&stack, instruction.B)
+			result, err = interpreter.callMember(pop(), chunk.Names[instruction.A], arguments, line, col)
+		case OpSuper:
+			arguments := popArguments(&stack, instruction.B)
+			tree := chunk.Tokens[instruction.A]
+			var this, class *OtterValue
+			this, class, err = interpreter.resolveSuper(tree)
+			if err == nil {
+				result, err = interpreter.callSuper(this, class, tree, arguments)
+			}
+		case OpGetItem:
+			index := pop()
+			target := pop()
+			result, err = interpreter.callMethod(target, "getItem", []*OtterValue{index}, line, col)
+		case OpSetItem:
+			value := pop()
+			index := pop()
+			target := pop()
+			result, err = interpreter.callMethod(target, "setItem", []*OtterValue{index, value}, line, col)
+		case OpCheckFields:
+			err = checkHasFields(stack[len(stack)-1], chunk.Tokens[instruction.A])
+		case OpSetField:
+			value := pop()
+			target := pop()
+			target.Fields[chunk.Names[instruction.A]] = value
+			result = value
+		case OpArray:
+			elements := popArguments(&stack, instruction.A)
+			result = interpreter.NewArray(elements)
+		case OpMap:
+			result, err = ConstructMap(interpreter, popArguments(&stack, 2*instruction.A))
+		case OpFunction:
+			result, err = interpreter.NewUserDefinedFunction(chunk.Tokens[instruction.A])
+		case OpClass:
+			var parent *OtterValue
+			if instruction.B == 1 {
+				parent = pop()
+			}
+			result, err = interpreter.newClass(chunk.Tokens[instruction.A], parent)
+		case OpImport:
+			result, err = interpreter.importModule(chunk.Names[instruction.A], line, col)
+		case OpImportName:
+			result, err = importName(stack[len(stack)-1], chunk.Names[instruction.A], line, col)
+		case OpReturn:
+			if !chunk.IsFunction {
+				err = exception.New(exception.SyntaxError, "illegal return in global scope", line, col)
+				break
+			}
+			return pop(), nil
+		case OpThrow:
+			_, err = interpreter.throw(pop(), line, col)
+		case OpRethrow:
+			_, err = interpreter.rethrow(line, col)
+		case OpSetupTry:
+			handlers = append(handlers, tryHandler{
+				target:      instruction.A,
+				depth:       len(stack),
+				environment: frame.Environment,
+			})
+		case OpPopTry:
+			handlers = handlers[:len(handlers)-1]
+		case OpMatchCatch:
+			otterException := stack[len(stack)-1].Value.(*exception.OtterException)
+			if instruction.A >= 0 {
+				handledType := exception.ExceptionType(chunk.Names[instruction.A])
+				if handledType != exception.BaseException && handledType != otterException.Type {
+					pc = instruction.B
+				}
+			}
+		case OpEnterCatch:
+			exceptionValue := pop()
+			caught = append(caught, frame.Exception)
+			frame.Exception = exceptionValue.Value.(*exception.OtterException)
+			// The caught exception is only visible inside the catch block
+			interpreter.CallStack.PushScope(chunk.Layouts[instruction.A].names)
+			frame.Environment.Slots[0] = exceptionValue
+		case OpLeaveCatch:
+			interpreter.CallStack.PopScope()
+			frame.Exception = caught[len(caught)-1]
+			caught = caught[:len(caught)-1]
+		case OpReraise:
+			err = pop().Value.(*exception.OtterException)
+		default:
+			err = exception.New(exception.InternalError, fmt.Sprintf("unknown opcode %v", instruction.Op), line, col)
+		}
+
+		if result != nil {
+			stack = append(stack, result)
+		}
+		if err == nil {
+			continue
+		}
+		if len(handlers) == 0 || isControlFlowSignal(err) {
+			// Leave the frame as it was found, like the tree walking
+			// interpreter does as the exception unwinds it
+			frame.Environment = environment
+			if len(caught) > 0 {
+				frame.Exception = caught[0]
+			}
+			return nil, err
+		}
+		handler := handlers[len(handlers)-1]
+		handlers = handlers[:len(handlers)-1]
+		frame.Environment = handler.environment
+		stack = append(stack[:handler.depth], interpreter.exceptionValueFromError(err))
+		pc = handler.target
+	}
+	if len(stack) == 0 {
+		return interpreter.NewNull(), nil
+	}
+	return stack[len(stack)-1], nil
+}
+
+// Removes the top count values from the stack, returning them in the
+// order they were pushed
+func popArguments(stack *[]*OtterValue, count int) []*OtterValue {
+	values := *stack
+	arguments := make([]*OtterValue, count)
+	copy(arguments, values[len(values)-count:])
+	*stack = values[:len(values)-count]
+	return arguments
+}
+
+func (interpreter *Interpreter) binaryOperation(op Opcode, left *OtterValue, right *OtterValue, line int, col int) (*OtterValue, exception.Exception) {
+	switch op {
+	case OpAdd:
+		return interpreter.add(left, right, line, col)
+	case OpSubtract:
+		return interpreter.subtract(left, right, line, col)
+	case OpMultiply:
+		return interpreter.multiply(left, right, line, col)
+	case OpDivide:
+		return interpreter.divide(left, right, line, col)
+	case OpModulo:
+		return interpreter.modulo(left, right, line, col)
+	case OpEqual:
+		return interpreter.equal(left, right, line, col)
+	case OpNotEqual:
+		return interpreter.notEqual(left, right, line, col)
+	case OpLessThan:
+		return interpreter.compare("<", left, right, line, col)
+	case OpGreaterThan:
+		return interpreter.compare(">", left, right, line, col)
+	case OpLessThanOrEqual:
+		return interpreter.compareOrEqual("<", left, right, line, col)
+	case OpGreaterThanOrEqual:
+		return interpreter.compareOrEqual(">", left, right, line, col)
+	case OpAnd:
+		return interpreter.and(left, right), nil
+	case OpOr:
+		return interpreter.or(left, right), nil
+	case OpInstanceOf:
+		return interpreter.instanceOf(left, right, line, col)
+	}
+	return nil, exception.New(exception.InternalError, fmt.Sprintf("%v is not a binary operation", op), line, col)
+}
+
+// Evaluates statements with the virtual machine rather than the tree
+// walking interpreter
+func (interpreter *Interpreter) executeBytecode(statements []*parser.Token) (*OtterValue, exception.Exception) {
+	chunk, err := interpreter.compile("<script>", statements, false)
+	if err != nil {
+		return nil, err
+	}
+	value, err := interpreter.run(chunk)
+	if err != nil {
+		interpreter.recordStackTrace(err)
+		return nil, err
+	}
+	return value, nil
+}
@@ -1,17 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
+	"github.com/nicholasbailey/otter/exception"
 	"github.com/nicholasbailey/otter/interpreter"
 	"github.com/nicholasbailey/otter/parser"
 )
 
 func main() {
-	// TODO: fix this
+	rawSyntax := flag.Bool("raw-syntax", false, "print the syntax tree of the file and exit")
+	unsweetenedSyntax := flag.Bool("unsweetened-syntax", false, "print the syntax tree of the file after unsweetening and exit")
+	bytecode := flag.Bool("bytecode", false, "print the bytecode compiled from the file and exit")
+	strict := flag.Bool("strict", false, "make assignment to undeclared variables a NameError")
+	vm := flag.Bool("vm", false, "run code with the bytecode virtual machine")
+	flag.Parse()
 
-	path := os.Args[len(os.Args)-1]
+	if flag.Arg(0) == "test" {
+		os.Exit(runTestCommand(flag.Args()[1:], *strict, *vm))
+	}
+
+	if flag.NArg() < 1 {
+		repl := NewRepl(os.Stdin, os.Stdout)
+		repl.engine.Interpreter.Strict = *strict
+		repl.engine.Interpreter.Bytecode = *vm
+		repl.Run()
+		os.Exit(0)
+	}
+
+	path := flag.Arg(0)
 	file, err := os.Open(path)
 	if err != nil {
 		fmt.Printf("%v\n", err)
@@ -19,7 +38,7 @@ func main() {
 	}
 	defer file.Close()
 
-	if os.Args[1] == "--raw-syntax" {
+	if *rawSyntax {
 		spec := parser.NewOtterLanguage()
 		lexer := parser.NewLexer(file, spec)
 		parser := parser.NewTDOPParser(lexer)
@@ -33,7 +52,7 @@ func main() {
 			}
 			os.Exit(0)
 		}
-	} else if os.Args[1] == "--unsweetened-syntax" {
+	} else if *unsweetenedSyntax {
 		parser := parser.NewParser(file)
 		tokens, err := parser.Statements()
 		if err != nil {
@@ -45,11 +64,27 @@ func main() {
 			}
 			os.Exit(0)
 		}
+	} else if *bytecode {
+		parser := parser.NewParser(file)
+		tokens, err := parser.Statements()
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		engine := interpreter.NewEngine()
+		err = engine.Interpreter.Disassemble(tokens, os.Stdout)
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
 	engine := interpreter.NewEngine()
-	_, err = engine.Execute(file)
+	engine.Interpreter.Strict = *strict
+	engine.Interpreter.Bytecode = *vm
+	_, err = engine.ExecuteFile(path, file)
 	if err != nil {
-		fmt.Printf("%v\n", err)
+		fmt.Printf("%v\n", exception.Format(err))
 		os.Exit(1)
 	}
 	os.Exit(0)
@@ -1,5 +1,7 @@
 package parser
 
+import "strconv"
+
 // Factory functions for AST components.
 // Note that these are used by the unsweetener, but generally
 // not used by the parser itself which relies on the TDOP mechanics
@@ -97,6 +99,23 @@ func BuildAccess(
 	return newTree
 }
 
+func BuildDeclaration(
+	name *Token,
+	initializer *Token,
+	line int,
+	col int,
+) *Token {
+
+	newTree := &Token{
+		Symbol:   Let,
+		Value:    "let",
+		Line:     line,
+		Col:      col,
+		Children: []*Token{name, initializer},
+	}
+	return newTree
+}
+
 func BuildName(name string, line int, col int) *Token {
 	return &Token{
 		Symbol: Name,
@@ -105,3 +124,27 @@ func BuildName(name string, line int, col int) *Token {
 		Col:    col,
 	}
 }
+
+func BuildIntLiteral(value int, line int, col int) *Token {
+	return &Token{
+		Symbol: IntLiteral,
+		Value:  strconv.Itoa(value),
+		Line:   line,
+		Col:    col,
+	}
+}
+
+func BuildIndex(
+	target *Token,
+	index *Token,
+	line int,
+	col int,
+) *Token {
+	return &Token{
+		Symbol:   Index,
+		Value:    "[",
+		Line:     line,
+		Col:      col,
+		Children: []*Token{target, index},
+	}
+}
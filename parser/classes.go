@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Defines class statements like
+//
+//	class Point {
+//	    def init(x, y) { ... }
+//	    def length() { ... }
+//	}
+//
+//	class Point3D extends Point { ... }
+//
+// A Class token has the class name as its first child and a block
+// of method definitions as its second, followed by the name of the
+// parent type if the class extends one
+func (spec *LanguageSpecification) DefineClass(classKeyword Symbol, extendsKeyword Symbol) {
+	classStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		name, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if name.Symbol != Name {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected class name, got %v", name.Value), name.Line, name.Col)
+		}
+		var parent *Token
+		next, err := parser.Peek()
+		if err != nil {
+			return nil, err
+		}
+		if next.Symbol == extendsKeyword {
+			_, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			parent, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			if parent.Symbol != Name {
+				return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected parent type name, got %v", parent.Value), parent.Line, parent.Col)
+			}
+		}
+		body, err := parser.Block()
+		if err != nil {
+			return nil, err
+		}
+		for _, statement := range body.Children {
+			if statement.Symbol != FunctionDefinition {
+				return nil, exception.New(exception.SyntaxError, "class bodies can only contain method definitions", statement.Line, statement.Col)
+			}
+		}
+		token.Symbol = Class
+		token.Children = append(token.Children, name, body)
+		if parent != nil {
+			token.Children = append(token.Children, parent)
+		}
+		return token, nil
+	}
+	spec.DefineStatment(classKeyword, classStd)
+	spec.DefineEmpty(extendsKeyword)
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Defines a variable declaration statement of the form
+// 'let x = expression;' or 'let x;'. The resulting token has
+// the declared name as its first child and the initializer, if
+// any, as its second. If requireInitializer is true, as it is
+// for constants, the initializer cannot be omitted
+func (spec *LanguageSpecification) DefineDeclaration(keyword Symbol, declarationSymbol Symbol, requireInitializer bool) {
+	declarationStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = declarationSymbol
+		name, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if name.Symbol != Name {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected identifier, got %v", name.Value), name.Line, name.Col)
+		}
+		token.Children = append(token.Children, name)
+
+		next, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if next.Symbol == "=" {
+			initializer, err := parser.Expression(0)
+			if err != nil {
+				return nil, err
+			}
+			token.Children = append(token.Children, initializer)
+			next, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+		} else if requireInitializer {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("%v %v must be initialized", keyword, name.Value), next.Line, next.Col)
+		}
+		if !parser.IsStatementTerminator(next) {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated statement with %v", next.Value), next.Line, next.Col)
+		}
+		return token, nil
+	}
+
+	spec.DefineStatment(keyword, declarationStd)
+}
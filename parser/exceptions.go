@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Contains parser logic for try/catch/finally and throw statements
+
+// Parses the '(ExceptionType name)' or '(name)' clause following
+// a catch keyword. Returns the variable name token and the exception
+// type token, which is nil if the catch clause is unfiltered
+func parseCatchClause(parser *TDOPParser) (*Token, *Token, exception.Exception) {
+	openParens, err := parser.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if openParens.Symbol != "(" {
+		return nil, nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected ( after catch, got %v", openParens.Value), openParens.Line, openParens.Col)
+	}
+	first, err := parser.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if first.Symbol != Name {
+		return nil, nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected identifier, got %v", first.Value), first.Line, first.Col)
+	}
+	next, err := parser.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if next.Symbol == ")" {
+		return first, nil, nil
+	}
+	if next.Symbol != Name {
+		return nil, nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected identifier, got %v", next.Value), next.Line, next.Col)
+	}
+	closeParens, err := parser.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	if closeParens.Symbol != ")" {
+		return nil, nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated parentheses with symbol %v", closeParens.Value), closeParens.Line, closeParens.Col)
+	}
+	return next, first, nil
+}
+
+// Defines a try statement of the form
+//
+//	try { ... } catch (TypeError e) { ... } catch (e) { ... } finally { ... }
+//
+// The resulting Try token has the try block as its first child,
+// followed by one Catch token per catch clause and an optional
+// Finally token. Each Catch token has the variable name and the
+// block as its first two children, followed by the name of the
+// exception type it handles, if any.
+func (spec *LanguageSpecification) DefineTry(tryKeyword Symbol, catchKeyword Symbol, finallyKeyword Symbol) {
+	tryStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = Try
+		block, err := parser.Block()
+		if err != nil {
+			return nil, err
+		}
+		token.Children = append(token.Children, block)
+
+		next, err := parser.Peek()
+		if err != nil {
+			return nil, err
+		}
+		for next.Symbol == catchKeyword {
+			catchToken, err := parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			variable, exceptionType, err := parseCatchClause(parser)
+			if err != nil {
+				return nil, err
+			}
+			catchBlock, err := parser.Block()
+			if err != nil {
+				return nil, err
+			}
+			catchToken.Symbol = Catch
+			catchToken.Children = append(catchToken.Children, variable, catchBlock)
+			if exceptionType != nil {
+				catchToken.Children = append(catchToken.Children, exceptionType)
+			}
+			token.Children = append(token.Children, catchToken)
+			next, err = parser.Peek()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if next.Symbol == finallyKeyword {
+			finallyToken, err := parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			finallyBlock, err := parser.Block()
+			if err != nil {
+				return nil, err
+			}
+			finallyToken.Symbol = Finally
+			finallyToken.Children = append(finallyToken.Children, finallyBlock)
+			token.Children = append(token.Children, finallyToken)
+		}
+
+		if len(token.Children) == 1 {
+			return nil, exception.New(exception.SyntaxError, "try must be followed by catch or finally", token.Line, token.Col)
+		}
+		return token, nil
+	}
+
+	spec.DefineStatment(tryKeyword, tryStd)
+	spec.DefineEmpty(catchKeyword)
+	spec.DefineEmpty(finallyKeyword)
+}
+
+// Defines a throw statement. A throw with no expression
+// rethrows the exception currently being handled
+func (spec *LanguageSpecification) DefineThrow(throwKeyword Symbol) {
+	throwStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = Throw
+		next, err := parser.Peek()
+		if err != nil {
+			return nil, err
+		}
+		if !parser.IsStatementTerminator(next) {
+			expression, err := parser.Expression(0)
+			if err != nil {
+				return nil, err
+			}
+			token.Children = append(token.Children, expression)
+			next, err = parser.Peek()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if parser.IsStatementTerminator(next) {
+			_, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return token, nil
+	}
+
+	spec.DefineStatment(throwKeyword, throwStd)
+}
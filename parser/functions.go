@@ -6,6 +6,60 @@ import (
 	"github.com/nicholasbailey/otter/exception"
 )
 
+// Parses a parenthesized, comma separated list of parameter names
+// into a FunctionParameters token
+func parseFunctionParameters(parser *TDOPParser) (*Token, exception.Exception) {
+	openParens, err := parser.Next()
+	if err != nil {
+		return nil, err
+	}
+	if openParens.Symbol != "(" {
+		return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected (, got %v", openParens.Value), openParens.Line, openParens.Col)
+	}
+	parameters := []*Token{}
+	next, err := parser.Next()
+	if err != nil {
+		return nil, err
+	}
+	if next.Symbol != ")" {
+		for {
+			if next.Symbol != Name {
+				return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected parameter name, got %v", next.Value), next.Line, next.Col)
+			}
+			parameters = append(parameters, next)
+			further, err := parser.Peek()
+			if err != nil {
+				return nil, err
+			}
+			if further.Symbol != "," {
+				break
+			}
+			_, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			next, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+		}
+		close, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if close.Symbol != ")" {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated parentheses with symbol %v", close.Value), close.Line, close.Col)
+		}
+	}
+	return &Token{
+		Symbol:   FunctionParameters,
+		Value:    "(",
+		Line:     openParens.Line,
+		Col:      openParens.Col,
+		Children: parameters,
+	}, nil
+}
+
 func (spec *LanguageSpecification) DefineFunctionDefinition(defSymbol Symbol) {
 	defStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
 		token.Symbol = FunctionDefinition
@@ -18,59 +72,31 @@ func (spec *LanguageSpecification) DefineFunctionDefinition(defSymbol Symbol) {
 		}
 		token.Children = append(token.Children, functionName)
 
-		openParens, err := parser.Next()
+		parameterToken, err := parseFunctionParameters(parser)
 		if err != nil {
 			return nil, err
 		}
-		if openParens.Symbol != "(" {
-			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected (, got %v", openParens.Value), openParens.Line, openParens.Col)
-		}
-		parameters := []*Token{}
-		next, err := parser.Next()
+		token.Children = append(token.Children, parameterToken)
+		block, err := parser.Block()
 		if err != nil {
 			return nil, err
 		}
-		if next.Symbol != ")" {
-			for {
-				if next.Symbol != Name {
-					return nil, err
-				}
-				parameters = append(parameters, next)
-				further, err := parser.Peek()
-				if err != nil {
-					return nil, err
-				}
-				if further.Symbol != "," {
-					break
-				}
-				_, err = parser.Next()
-				if err != nil {
-					return nil, err
-				}
-				next, err = parser.Next()
-				if err != nil {
-					return nil, err
-				}
-			}
-			close, err := parser.Next()
-			if err != nil {
-				return nil, err
-			}
-			if close.Symbol != ")" {
-				return nil, fmt.Errorf("syntaxerror: unterminated parentheses with symbol %v at line %v, col %v", close.Value, close.Line, close.Col)
-			}
-		} else {
-			_, err = parser.Next()
-			if err != nil {
-				return nil, err
-			}
-		}
-		parameterToken := &Token{
-			Symbol:   FunctionParameters,
-			Value:    "(",
-			Line:     openParens.Line,
-			Col:      openParens.Col,
-			Children: parameters,
+		token.Children = append(token.Children, block)
+		return token, nil
+	}
+
+	spec.DefineStatment(defSymbol, defStd)
+}
+
+// Defines an anonymous function expression of the form
+// fn(x, y) { x + y }. Unlike a def, a function literal
+// is an expression and does not bind a name
+func (spec *LanguageSpecification) DefineFunctionLiteral(fnSymbol Symbol) {
+	fnNud := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = FunctionLiteral
+		parameterToken, err := parseFunctionParameters(parser)
+		if err != nil {
+			return nil, err
 		}
 		token.Children = append(token.Children, parameterToken)
 		block, err := parser.Block()
@@ -81,18 +107,26 @@ func (spec *LanguageSpecification) DefineFunctionDefinition(defSymbol Symbol) {
 		return token, nil
 	}
 
-	spec.DefineStatment(defSymbol, defStd)
+	spec.Define(fnSymbol, 0, 0, fnNud, nil, nil)
 }
 
+// Defines the return statement. A return with no expression, like
+// return;, has no children
 func (spec *LanguageSpecification) DefineReturn(returnSymbol Symbol) {
 	returnStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
-		expression, err := parser.Expression(0)
+		next, err := parser.Peek()
 		if err != nil {
 			return nil, err
 		}
-		token.Children = append(token.Children, expression)
+		if !parser.IsStatementTerminator(next) && !parser.Lexer.IsAnyBlockEnd(next) {
+			expression, err := parser.Expression(0)
+			if err != nil {
+				return nil, err
+			}
+			token.Children = append(token.Children, expression)
+		}
 		// Hack, something is wonky here
-		next, err := parser.Peek()
+		next, err = parser.Peek()
 		if err != nil {
 			return nil, err
 		}
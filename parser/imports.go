@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Defines import statements of the forms 'import "path";',
+// 'import "path" as name;' and 'from "path" import a, b;'. The module
+// path can also be written as a plain name, as in 'import math;'.
+//
+// An import token has the module path, as a string literal, as its
+// first child and the name the module is bound to as its second.
+// Without an explicit name, a module is bound to the last part of its
+// path. A from import token has the module path as its first child and
+// the names imported from the module as the rest
+func (spec *LanguageSpecification) DefineImport(importKeyword Symbol, fromKeyword Symbol, asKeyword string) {
+	importStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = Import
+		modulePath, err := parseModulePath(parser)
+		if err != nil {
+			return nil, err
+		}
+		token.Children = append(token.Children, modulePath)
+
+		next, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if next.Symbol == Name && next.Value == asKeyword {
+			name, err := parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			if name.Symbol != Name {
+				return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected identifier, got %v", name.Value), name.Line, name.Col)
+			}
+			token.Children = append(token.Children, name)
+			next, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			name := strings.TrimSuffix(path.Base(modulePath.Value), path.Ext(modulePath.Value))
+			if !isIdentifier(name) {
+				return nil, exception.New(exception.SyntaxError, fmt.Sprintf("module %v needs a name to be imported as", modulePath.Value), modulePath.Line, modulePath.Col)
+			}
+			token.Children = append(token.Children, BuildName(name, modulePath.Line, modulePath.Col))
+		}
+		if !parser.IsStatementTerminator(next) {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated statement with %v", next.Value), next.Line, next.Col)
+		}
+		return token, nil
+	}
+
+	fromStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = FromImport
+		modulePath, err := parseModulePath(parser)
+		if err != nil {
+			return nil, err
+		}
+		token.Children = append(token.Children, modulePath)
+
+		next, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if next.Symbol != importKeyword {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected %v, got %v", importKeyword, next.Value), next.Line, next.Col)
+		}
+		for {
+			name, err := parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			if name.Symbol != Name {
+				return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected identifier, got %v", name.Value), name.Line, name.Col)
+			}
+			token.Children = append(token.Children, name)
+			next, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			if next.Symbol != "," {
+				break
+			}
+		}
+		if !parser.IsStatementTerminator(next) {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated statement with %v", next.Value), next.Line, next.Col)
+		}
+		return token, nil
+	}
+
+	spec.DefineStatment(importKeyword, importStd)
+	spec.DefineStatment(fromKeyword, fromStd)
+}
+
+// Parses the path of the module an import statement imports. A path
+// written as a plain name becomes a string literal too
+func parseModulePath(parser *TDOPParser) (*Token, exception.Exception) {
+	modulePath, err := parser.Next()
+	if err != nil {
+		return nil, err
+	}
+	if modulePath.Symbol != StringLiteral && modulePath.Symbol != Name {
+		return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected module path, got %v", modulePath.Value), modulePath.Line, modulePath.Col)
+	}
+	modulePath.Symbol = StringLiteral
+	return modulePath, nil
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Defines index expressions of the form target[index]. The resulting
+// Index token has the target as its first child and the index as
+// its second
+func (spec *LanguageSpecification) DefineIndex(openIndex Symbol, closeIndex Symbol) {
+	indexLed := func(token *Token, parser *TDOPParser, left *Token) (*Token, exception.Exception) {
+		index, err := parser.Expression(0)
+		if err != nil {
+			return nil, err
+		}
+		close, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if close.Symbol != closeIndex {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated index with symbol %v", close.Value), close.Line, close.Col)
+		}
+		token.Symbol = Index
+		token.Children = append(token.Children, left, index)
+		return token, nil
+	}
+
+	// Indexing binds as tightly as property access so that chains
+	// like x.items()[0] index the result of the method call
+	spec.Define(openIndex, 100, 2, nil, indexLed, nil)
+	spec.DefineEmpty(closeIndex)
+}
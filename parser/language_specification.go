@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"unicode"
 
 	"github.com/nicholasbailey/otter/exception"
@@ -36,6 +37,8 @@ type LanguageSpecification struct {
 	statementTerminators []Symbol
 	blockDelimiters      map[Symbol]Symbol
 	commentStarts        []Symbol
+	// The symbol separating a label from the loop it labels
+	labelSeparator Symbol
 }
 
 func (spec *LanguageSpecification) DefineComment(symbol Symbol) {
@@ -68,6 +71,13 @@ func (spec *LanguageSpecification) DefineBlock(startSymbol Symbol, endSymbol Sym
 		if err != nil {
 			return nil, err
 		}
+		end, err := parser.Next()
+		if err != nil {
+			return nil, err
+		}
+		if end.Symbol != endSymbol {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected %v to close block, but got %v", endSymbol, end.Value), end.Line, end.Col)
+		}
 		token.Children = append(token.Children, statements...)
 		token.Symbol = Block
 		return token, nil
@@ -104,6 +114,16 @@ func (spec *LanguageSpecification) IsStatementTerminator(symbol Symbol) bool {
 	return false
 }
 
+func (spec *LanguageSpecification) IsLabelSeparator(symbol Symbol) bool {
+	return spec.labelSeparator != "" && symbol == spec.labelSeparator
+}
+
+// Allows loops to be labeled with a name followed by the separator
+func (spec *LanguageSpecification) DefineLabelSeparator(symbol Symbol) {
+	spec.DefineEmpty(symbol)
+	spec.labelSeparator = symbol
+}
+
 func (spec *LanguageSpecification) DefineStatementTerminator(symbol Symbol) {
 	spec.statementTerminators = append(spec.statementTerminators, symbol)
 	spec.symbols[symbol] = &Token{
@@ -235,7 +255,9 @@ func (spec *LanguageSpecification) DefinePrefix(symbol Symbol, bindingPower int)
 		t.Children = append(t.Children, expResult)
 		return t, nil
 	}
-	spec.Define(symbol, bindingPower, 1, nud, nil, nil)
+	// The binding power only applies to the operand. Defining the symbol with
+	// it would change the precedence of an infix operator sharing the symbol
+	spec.Define(symbol, 0, 1, nud, nil, nil)
 }
 
 // come up with a better name for this
@@ -86,8 +86,12 @@ func (lexer *Lexer) IsStatementTerminator(token *Token) bool {
 	return lexer.languageSpec.IsStatementTerminator(token.Symbol)
 }
 
-func (lexer *Lexer) syntaxError(msg string) error {
-	return fmt.Errorf("syntaxerror: "+msg+" at line %v, col %v", lexer.line, lexer.col)
+func (lexer *Lexer) IsLabelSeparator(token *Token) bool {
+	return lexer.languageSpec.IsLabelSeparator(token.Symbol)
+}
+
+func (lexer *Lexer) syntaxError(msg string) exception.Exception {
+	return exception.New(exception.SyntaxError, msg, lexer.line, lexer.col)
 }
 
 func (lexer *Lexer) startOfToken(char rune) {
@@ -118,7 +122,7 @@ func (lexer *Lexer) endOfToken() (*Token, error) {
 	case stringLiteral:
 		quoteSpec := lexer.languageSpec.GetQuoteSpec(lexer.currentQuoteStart)
 		if quoteSpec == nil {
-			return nil, fmt.Errorf("syntaxerror: invalid quoted literal with quote %v at line %v, col %v", string(lexer.currentQuoteStart), lexer.line, lexer.col)
+			return nil, lexer.syntaxError(fmt.Sprintf("invalid quoted literal with quote %v", string(lexer.currentQuoteStart)))
 		}
 		stringVal := lexer.builder.String()
 		token := lexer.languageSpec.GenerateToken(StringLiteral, stringVal, lexer.line, lexer.tokenStartCol)
@@ -154,17 +158,17 @@ func (lexer *Lexer) endOfToken() (*Token, error) {
 		if lexer.languageSpec.IsDefined(Symbol(stringVal)) {
 			token = lexer.languageSpec.GenerateToken(Symbol(stringVal), stringVal, lexer.line, lexer.tokenStartCol)
 		} else {
-			return nil, fmt.Errorf("syntaxerror: unidentified operator %v at line %v, col %v", stringVal, lexer.line, lexer.col)
+			return nil, lexer.syntaxError(fmt.Sprintf("unidentified operator %v", stringVal))
 		}
 		lexer.builder = strings.Builder{}
 		lexer.tokenStartCol = lexer.col
 		return token, nil
 	case whiteSpace:
-		return nil, fmt.Errorf("syntaxerror: attempted to resolve token in whitespace at line %v, col %v", lexer.line, lexer.col)
+		return nil, lexer.syntaxError("attempted to resolve token in whitespace")
 	case comment:
-		return nil, exception.New(exception.SyntaxError, "attempted to resolve token in comment", lexer.line, lexer.col)
+		return nil, lexer.syntaxError("attempted to resolve token in comment")
 	default:
-		return nil, fmt.Errorf("syntaxerror: attempted to resolve token in unkown parse state at line %v, col %v", lexer.line, lexer.col)
+		return nil, lexer.syntaxError("attempted to resolve token in unkown parse state")
 	}
 }
 
@@ -237,7 +241,7 @@ func (lexer *Lexer) Next() (*Token, error) {
 			quoteSpecification := lexer.languageSpec.GetQuoteSpec(lexer.currentQuoteStart)
 			if quoteSpecification == nil {
 				// This should never happen,
-				return nil, fmt.Errorf("syntaxerror: unrecognized quote character '%v'", string(lexer.currentQuoteStart))
+				return nil, lexer.syntaxError(fmt.Sprintf("unrecognized quote character '%v'", string(lexer.currentQuoteStart)))
 			}
 			if char == quoteSpecification.closeQuote {
 				token, err = lexer.endOfToken()
@@ -276,7 +280,7 @@ func (lexer *Lexer) Next() (*Token, error) {
 					}
 					lexer.startOfToken(char)
 				} else {
-					return nil, fmt.Errorf("syntaxerror: unrecognized operator %v at line %v, col %v", string(char), lexer.line, lexer.col)
+					return nil, lexer.syntaxError(fmt.Sprintf("unrecognized operator %v", string(char)))
 				}
 			}
 		case comment:
@@ -284,7 +288,7 @@ func (lexer *Lexer) Next() (*Token, error) {
 				lexer.currentState = unknown
 			}
 		default:
-			return nil, fmt.Errorf("syntaxerror: invalid lexer state state %v at line %v, col %v", lexer.currentState, lexer.line, lexer.col)
+			return nil, exception.New(exception.InternalError, fmt.Sprintf("invalid lexer state %v", lexer.currentState), lexer.line, lexer.col)
 		}
 
 		if token != nil {
@@ -296,7 +300,7 @@ func (lexer *Lexer) Next() (*Token, error) {
 	if errors.Is(err, io.EOF) {
 		switch lexer.currentState {
 		case stringLiteral:
-			return nil, fmt.Errorf("syntaxerror: unexpected EOF in string literal at line %v, col %v", lexer.line, lexer.col)
+			return nil, lexer.syntaxError("unexpected EOF in string literal")
 		case eof:
 			return lexer.languageSpec.Eof(lexer.line, lexer.col), nil
 		default:
@@ -313,5 +317,5 @@ func (lexer *Lexer) Next() (*Token, error) {
 			}
 		}
 	}
-	return nil, fmt.Errorf("syntaxerror: unreadable character %v at line %v, col %v", string(char), lexer.line, lexer.col)
+	return nil, lexer.syntaxError(fmt.Sprintf("unreadable character %v", string(char)))
 }
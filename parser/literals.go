@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nicholasbailey/otter/exception"
+)
+
+// Contains parser logic for collection literals
+
+// Parses a comma separated sequence of elements up to and including the
+// close symbol. A trailing comma before the close symbol is allowed
+func parseDelimitedElements(parser *TDOPParser, closeSymbol Symbol, parseElement func() exception.Exception) exception.Exception {
+	for {
+		next, err := parser.Peek()
+		if err != nil {
+			return err
+		}
+		if next.Symbol == closeSymbol {
+			_, err = parser.Next()
+			return err
+		}
+		err = parseElement()
+		if err != nil {
+			return err
+		}
+		separator, err := parser.Next()
+		if err != nil {
+			return err
+		}
+		if separator.Symbol == closeSymbol {
+			return nil
+		}
+		if separator.Symbol != "," {
+			return exception.New(exception.SyntaxError, fmt.Sprintf("expected , or %v but got %v", closeSymbol, separator.Value), separator.Line, separator.Col)
+		}
+	}
+}
+
+// Defines array literals of the form [a, b, c]. The resulting
+// ArrayLiteral token has one child per element
+func (spec *LanguageSpecification) DefineArrayLiteral(openArray Symbol, closeArray Symbol) {
+	nud := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		err := parseDelimitedElements(parser, closeArray, func() exception.Exception {
+			element, err := parser.Expression(0)
+			if err != nil {
+				return err
+			}
+			token.Children = append(token.Children, element)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		token.Symbol = ArrayLiteral
+		return token, nil
+	}
+	spec.Define(openArray, 0, 0, nud, nil, nil)
+	spec.DefineEmpty(closeArray)
+}
+
+// Defines map literals of the form {key: value, key: value}. The
+// open symbol is usually also a block start. Blocks are statements
+// and maps are expressions, so a block is parsed wherever a statement
+// starts with the open symbol and a map is parsed everywhere else.
+// The resulting MapLiteral token has alternating key and value children
+func (spec *LanguageSpecification) DefineMapLiteral(openMap Symbol, closeMap Symbol, keyValueSeparator Symbol) {
+	nud := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		err := parseDelimitedElements(parser, closeMap, func() exception.Exception {
+			key, err := parser.Expression(0)
+			if err != nil {
+				return err
+			}
+			separator, err := parser.Next()
+			if err != nil {
+				return err
+			}
+			if separator.Symbol != keyValueSeparator {
+				return exception.New(exception.SyntaxError, fmt.Sprintf("expected %v after map key but got %v", keyValueSeparator, separator.Value), separator.Line, separator.Col)
+			}
+			value, err := parser.Expression(0)
+			if err != nil {
+				return err
+			}
+			token.Children = append(token.Children, key, value)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		token.Symbol = MapLiteral
+		return token, nil
+	}
+	spec.Define(openMap, 0, 0, nud, nil, nil)
+	spec.DefineEmpty(closeMap)
+	spec.DefineEmpty(keyValueSeparator)
+}
@@ -8,15 +8,31 @@ import (
 
 func (spec *LanguageSpecification) DefineForIn(forKeyword Symbol, inKeyword Symbol) {
 	forStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
-		loopVarToken, err := parser.Next()
-		if err != nil {
-			return nil, err
+		// Either a single loop variable, or a pair of variables
+		// separated by a comma
+		loopVarTokens := []*Token{}
+		for {
+			loopVarToken, err := parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			if loopVarToken.Symbol != Name {
+				errorMsg := fmt.Sprintf("Unexpected symbol %v in for expression", loopVarToken.Value)
+				return nil, exception.New(exception.SyntaxError, errorMsg, loopVarToken.Line, loopVarToken.Col)
+			}
+			loopVarTokens = append(loopVarTokens, loopVarToken)
+			separator, err := parser.Peek()
+			if err != nil {
+				return nil, err
+			}
+			if separator.Symbol != "," {
+				break
+			}
+			if len(loopVarTokens) == 2 {
+				return nil, exception.New(exception.SyntaxError, "for loops take at most two loop variables", separator.Line, separator.Col)
+			}
+			parser.Next()
 		}
-		if loopVarToken.Symbol != Name {
-			errorMsg := fmt.Sprintf("Unexpected symbol %v in for expression", loopVarToken.Value)
-			return nil, exception.New(exception.SyntaxError, errorMsg, loopVarToken.Line, loopVarToken.Col)
-		}
-		// TODO support tuply syntax here
 		inToken, err := parser.Next()
 		if err != nil {
 			return nil, err
@@ -33,8 +49,10 @@ func (spec *LanguageSpecification) DefineForIn(forKeyword Symbol, inKeyword Symb
 			return nil, err
 		}
 
+		// The loop variables come first, followed by the
+		// iterable and the block
 		token.Symbol = ForIn
-		token.Children = append(token.Children, loopVarToken)
+		token.Children = append(token.Children, loopVarTokens...)
 		token.Children = append(token.Children, rangeToken)
 		token.Children = append(token.Children, blockToken)
 		return token, nil
@@ -62,3 +80,88 @@ func (spec *LanguageSpecification) DefineWhile(whileKeyword Symbol) {
 
 	spec.DefineStatment(whileKeyword, whileStd)
 }
+
+// Defines a statement like break or continue, which may be followed
+// by the label of the loop it applies to
+func (spec *LanguageSpecification) DefineLoopControl(keyword Symbol, symbol Symbol) {
+	loopControlStd := func(token *Token, parser *TDOPParser) (*Token, exception.Exception) {
+		token.Symbol = symbol
+		next, err := parser.Peek()
+		if err != nil {
+			return nil, err
+		}
+		if next.Symbol == Name {
+			label, err := parser.Next()
+			if err != nil {
+				return nil, err
+			}
+			token.Children = append(token.Children, label)
+			next, err = parser.Peek()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if parser.IsStatementTerminator(next) {
+			_, err = parser.Next()
+			if err != nil {
+				return nil, err
+			}
+		} else if !parser.Lexer.IsAnyBlockEnd(next) {
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated statement with %v", next.Value), next.Line, next.Col)
+		}
+		return token, nil
+	}
+	spec.DefineStatment(keyword, loopControlStd)
+}
+
+// Checks that every break and continue statement in an unsweetened
+// tree is inside a loop, and that any label it names belongs to a
+// loop enclosing it. Loops outside a function don't enclose the
+// statements in its body
+func CheckLoopControl(tree *Token) exception.Exception {
+	return checkLoopControl(tree, false, []string{})
+}
+
+func checkLoopControl(tree *Token, inLoop bool, labels []string) exception.Exception {
+	switch tree.Symbol {
+	case FunctionDefinition, FunctionLiteral:
+		inLoop = false
+		labels = []string{}
+	case Break, Continue:
+		if !inLoop {
+			return exception.New(exception.SyntaxError, fmt.Sprintf("%v outside of a loop", tree.Value), tree.Line, tree.Col)
+		}
+		if len(tree.Children) > 0 && !containsLabel(labels, tree.Children[0].Value) {
+			label := tree.Children[0]
+			return exception.New(exception.SyntaxError, fmt.Sprintf("no enclosing loop is labeled %v", label.Value), label.Line, label.Col)
+		}
+	case While:
+		// The condition is checked in the enclosing context,
+		// and the body in the context of this loop
+		err := checkLoopControl(tree.Children[0], inLoop, labels)
+		if err != nil {
+			return err
+		}
+		loopLabels := labels
+		if len(tree.Children) > 2 {
+			loopLabels = append(append([]string{}, labels...), tree.Children[2].Value)
+		}
+		return checkLoopControl(tree.Children[1], true, loopLabels)
+	}
+	for _, child := range tree.Children {
+		err := checkLoopControl(child, inLoop, labels)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
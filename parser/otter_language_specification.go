@@ -6,16 +6,28 @@ func NewOtterLanguage() *LanguageSpecification {
 
 	spec.DefineWhile("while")
 	spec.DefineForIn("for", "in")
+	spec.DefineLoopControl("break", Break)
+	spec.DefineLoopControl("continue", Continue)
 	spec.DefineIf("if", "else")
 	spec.DefineAccess(".")
 	spec.DefineComment("//")
 	spec.DefineParens("(", ")")
+	spec.DefineIndex("[", "]")
+	spec.DefineArrayLiteral("[", "]")
 	spec.DefineQuotes('"', '"', StringLiteral)
 	spec.DefineQuotes('\'', '\'', StringLiteral)
 	spec.DefineReturn("return")
 	spec.DefineFunctionDefinition("def")
+	spec.DefineFunctionLiteral("fn")
+	spec.DefineClass("class", "extends")
+	spec.DefineTry("try", "catch", "finally")
+	spec.DefineThrow("throw")
+	spec.DefineDeclaration("let", Let, false)
+	spec.DefineDeclaration("const", Const, true)
+	spec.DefineImport("import", "from", "as")
 
 	spec.DefinePrefix("!", 80)
+	spec.DefinePrefix("-", 80)
 	spec.DefineInfix("&&", "&&", 30)
 	spec.DefineInfix("||", "||", 20)
 	spec.DefineInfix("=", Assignment, 10)
@@ -25,6 +37,7 @@ func NewOtterLanguage() *LanguageSpecification {
 	spec.DefineInfix(">", ">", 50)
 	spec.DefineInfix("<=", "<=", 50)
 	spec.DefineInfix(">=", ">=", 50)
+	spec.DefineInfix("instanceof", "instanceof", 50)
 	spec.DefineInfix("+", "+", 60)
 	spec.DefineInfix("-", "-", 60)
 	spec.DefineInfix("*", "*", 70)
@@ -32,7 +45,9 @@ func NewOtterLanguage() *LanguageSpecification {
 	spec.DefineInfix("%", "%", 70)
 	spec.DefineStatementTerminator(";")
 	spec.DefineEmpty(",")
+	spec.DefineLabelSeparator(":")
 	spec.DefineBlock("{", "}")
+	spec.DefineMapLiteral("{", "}", ":")
 	spec.DefineValue("true")
 	spec.DefineValue("false")
 
@@ -18,7 +18,7 @@ func (spec *LanguageSpecification) DefineParens(openParens Symbol, closeParens S
 			return nil, err
 		}
 		if next.Symbol != closeParens {
-			return nil, fmt.Errorf("SyntaxError: Unterminated braces at line %v, col %v", next.Line, next.Col)
+			return nil, exception.New(exception.SyntaxError, "unterminated braces", next.Line, next.Col)
 		}
 		return expressionToken, nil
 	}
@@ -26,9 +26,8 @@ func (spec *LanguageSpecification) DefineParens(openParens Symbol, closeParens S
 	spec.DefineValue(closeParens)
 
 	openParensLed := func(right *Token, parser *TDOPParser, left *Token) (*Token, exception.Exception) {
-		if left.Symbol != Name && left.Symbol != Symbol("(") {
-			return nil, fmt.Errorf("syntaxerror: unexpected ( at line %v, col %v", right.Line, right.Col)
-		}
+		// Any expression can be called. Calling a value that isn't a
+		// function is a TypeError when it happens
 		right.Children = append(right.Children, left)
 		t, err := parser.Peek()
 		if err != nil {
@@ -58,7 +57,7 @@ func (spec *LanguageSpecification) DefineParens(openParens Symbol, closeParens S
 				return nil, err
 			}
 			if close.Symbol != ")" {
-				return nil, fmt.Errorf("syntaxerror: unterminated parentheses with symbol %v at line %v, col %v", close.Value, close.Line, close.Col)
+				return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated parentheses with symbol %v", close.Value), close.Line, close.Col)
 			}
 		} else {
 			_, err = parser.Next()
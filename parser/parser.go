@@ -45,6 +45,10 @@ func (otterParser *OtterParser) Statements() ([]*Token, exception.Exception) {
 		if err != nil {
 			return nil, err
 		}
+		err = CheckLoopControl(unsweetened)
+		if err != nil {
+			return nil, err
+		}
 		newStatements = append(newStatements, unsweetened)
 	}
 	return newStatements, nil
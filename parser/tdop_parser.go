@@ -38,15 +38,10 @@ func (parser *TDOPParser) Block() (*Token, error) {
 		return nil, err
 	}
 	if !parser.Lexer.IsBlockStart(token) {
-		return nil, fmt.Errorf("syntaxerror: expected block start, but got %v at line %v, col %v", token.Value, token.Line, token.Col)
+		return nil, exception.New(exception.SyntaxError, fmt.Sprintf("expected block start, but got %v", token.Value), token.Line, token.Col)
 	}
 
-	block, err := token.Std(token, parser)
-	if err != nil {
-		return nil, err
-	}
-	parser.Lexer.Next()
-	return block, nil
+	return token.Std(token, parser)
 }
 
 func (parser *TDOPParser) Statement() (*Token, error) {
@@ -66,16 +61,47 @@ func (parser *TDOPParser) Statement() (*Token, error) {
 	if err != nil {
 		return nil, err
 	}
-	terminator, err := parser.Lexer.Next()
+	terminator, err := parser.Lexer.Peek()
+	if err != nil {
+		return nil, err
+	}
+	if res.Symbol == Name && parser.Lexer.IsLabelSeparator(terminator) {
+		return parser.labeledStatement(res)
+	}
+	// The terminator may be omitted on the last statement of
+	// a block, allowing expression bodies like fn(x) { x * 2 }
+	if parser.Lexer.IsAnyBlockEnd(terminator) {
+		return res, nil
+	}
 	if !parser.Lexer.IsStatementTerminator(terminator) {
 		return nil, exception.New(exception.SyntaxError, fmt.Sprintf("unterminated statement with %v", terminator.Value), terminator.Line, terminator.Col)
 	}
+	_, err = parser.Lexer.Next()
 	if err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
+// Parses the loop following a label. The label is the
+// value of the returned token, and the loop its only child
+func (parser *TDOPParser) labeledStatement(label *Token) (*Token, error) {
+	_, err := parser.Lexer.Next()
+	if err != nil {
+		return nil, err
+	}
+	loop, err := parser.Statement()
+	if err != nil {
+		return nil, err
+	}
+	if loop.Symbol != While && loop.Symbol != ForIn {
+		return nil, exception.New(exception.SyntaxError, fmt.Sprintf("label %v must be followed by a loop", label.Value), label.Line, label.Col)
+	}
+	label.Symbol = Labeled
+	label.Children = []*Token{loop}
+	return label, nil
+}
+
 func (parser *TDOPParser) Statements() ([]*Token, exception.Exception) {
 	statements := []*Token{}
 	next, err := parser.Lexer.Peek()
@@ -123,7 +149,7 @@ func (parser *TDOPParser) Expression(rightBindingPower int) (*Token, exception.E
 			return nil, err
 		}
 		if t.Led == nil {
-			return nil, fmt.Errorf("%v is not a valid infix symbol", t.Symbol)
+			return nil, exception.New(exception.SyntaxError, fmt.Sprintf("%v is not a valid infix symbol", t.Value), t.Line, t.Col)
 		}
 		left, err = t.Led(t, parser, left)
 		if err != nil {
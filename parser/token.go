@@ -35,13 +35,45 @@ const (
 	FunctionDefinition Symbol = "(FUNCTIONDEFINITION)"
 	// Symbol for a function parameters
 	FunctionParameters Symbol = "(FUNCTIONPARAMETERS)"
+	// Symbol for an anonymous function expression
+	FunctionLiteral Symbol = "(FUNCTIONLITERAL)"
 	// Symbol for a function invocation
 	FunctionInvocation Symbol = "(FUNCTIONINVOCATION)"
 	Access             Symbol = "(ACCESS)"
-	While              Symbol = "(WHILE)"
-	Comment            Symbol = "(COMMENT)"
-	ForIn              Symbol = "(FORIN)"
-	Assignment         Symbol = "(ASSIGNMENT)"
+	// Symbol for an index expression like x[i]
+	Index Symbol = "(INDEX)"
+	// Symbol for an array literal like [1, 2, 3]
+	ArrayLiteral Symbol = "(ARRAYLITERAL)"
+	// Symbol for a map literal like {"a": 1}
+	MapLiteral Symbol = "(MAPLITERAL)"
+	While      Symbol = "(WHILE)"
+	Comment    Symbol = "(COMMENT)"
+	ForIn      Symbol = "(FORIN)"
+	Assignment Symbol = "(ASSIGNMENT)"
+	// Symbol for a variable declared with let
+	Let Symbol = "(LET)"
+	// Symbol for a constant declared with const
+	Const Symbol = "(CONST)"
+	// Symbol for a try statement
+	Try Symbol = "(TRY)"
+	// Symbol for a catch clause of a try statement
+	Catch Symbol = "(CATCH)"
+	// Symbol for a finally clause of a try statement
+	Finally Symbol = "(FINALLY)"
+	// Symbol for a throw statement
+	Throw Symbol = "(THROW)"
+	// Symbol for a loop with a label, like outer: while x { }
+	Labeled Symbol = "(LABELED)"
+	// Symbol for a break statement
+	Break Symbol = "(BREAK)"
+	// Symbol for a continue statement
+	Continue Symbol = "(CONTINUE)"
+	// Symbol for a class definition
+	Class Symbol = "(CLASS)"
+	// Symbol for an import statement that binds a module to a name
+	Import Symbol = "(IMPORT)"
+	// Symbol for an import statement that binds names a module exports
+	FromImport Symbol = "(FROMIMPORT)"
 )
 
 type NudFunction func(right *Token, parser *TDOPParser) (*Token, exception.Exception)
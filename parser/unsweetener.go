@@ -11,6 +11,7 @@ type Unsweetener interface {
 func NewUnsweetener() Unsweetener {
 	unsweeteningRules := map[Symbol]UnsweetingRule{}
 	unsweeteningRules[ForIn] = UnsweetenForIn
+	unsweeteningRules[Labeled] = UnsweetenLabeled
 	return &SimpleUnsweeter{
 		UnsweeteningRules: unsweeteningRules,
 	}
@@ -22,7 +23,16 @@ type SimpleUnsweeter struct {
 	UnsweeteningRules map[Symbol]UnsweetingRule
 }
 
+// Unsweetens a tree from the bottom up, so sugar nested anywhere
+// in the tree, like a for loop inside a function, is removed
 func (unsweetener *SimpleUnsweeter) Unsweeten(tree *Token) (*Token, exception.Exception) {
+	for i, child := range tree.Children {
+		unsweetenedChild, err := unsweetener.Unsweeten(child)
+		if err != nil {
+			return nil, err
+		}
+		tree.Children[i] = unsweetenedChild
+	}
 	rule, found := unsweetener.UnsweeteningRules[tree.Symbol]
 	if found {
 		return rule(tree)
@@ -31,52 +41,77 @@ func (unsweetener *SimpleUnsweeter) Unsweeten(tree *Token) (*Token, exception.Ex
 }
 
 // Converts the syntax tree for a for-in loop to a while
-// loop
+// loop. A loop with one variable visits each element produced by
+// the iterable's iterator. A loop with two variables instead uses
+// the iterable's entryIterator, which produces two element arrays,
+// and unpacks each array into the two variables
 func UnsweetenForIn(tree *Token) (*Token, exception.Exception) {
+	loopVariableTokens := tree.Children[:len(tree.Children)-2]
+	iterableToken := tree.Children[len(tree.Children)-2]
+	originalBlockToken := tree.Children[len(tree.Children)-1]
+	line, col := iterableToken.Line, iterableToken.Col
 
-	iterationVariableToken := tree.Children[0]
-	iterableToken := tree.Children[1]
-	originalBlockToken := tree.Children[2]
-
-	iterationVariableName := iterationVariableToken.Value
-	iteratorVariableName := "~" + iterationVariableName + "Iterator"
+	firstVariableName := loopVariableTokens[0].Value
+	iteratorVariableName := "~" + firstVariableName + "Iterator"
+	iteratorMethod := "iterator"
+	if len(loopVariableTokens) == 2 {
+		iteratorMethod = "entryIterator"
+	}
 
-	iterationVariableInitalizer := BuildAssignment(
-		BuildName(iterationVariableName, iterationVariableToken.Line, iterationVariableToken.Col),
-		BuildName("null", iterationVariableToken.Line, iterationVariableToken.Col),
-		iterationVariableToken.Line,
-		iterationVariableToken.Col,
-	)
-	iteratorInitializer := BuildAssignment(
-		BuildName(iteratorVariableName, iterableToken.Line, iterableToken.Col),
-		BuildAccess(iterableToken, "iterator", []*Token{}, iterableToken.Line, iterableToken.Col),
-		iterableToken.Line,
-		iterableToken.Col,
-	)
+	// The iterator is declared once, in a block around the loop
+	declarations := []*Token{BuildDeclaration(
+		BuildName(iteratorVariableName, line, col),
+		BuildAccess(iterableToken, iteratorMethod, []*Token{}, line, col),
+		line,
+		col,
+	)}
 
 	whileConditionalExpression := BuildAccess(
-		BuildName(iteratorVariableName, iterableToken.Line, iterableToken.Col),
+		BuildName(iteratorVariableName, line, col),
 		"hasNext",
 		[]*Token{},
-		iterableToken.Line,
-		iterableToken.Col,
+		line,
+		col,
 	)
 
-	newBlockChildren := []*Token{
-		BuildAssignment(
-			BuildName(iterationVariableName, iterationVariableToken.Line, iterationVariableToken.Col),
-			BuildAccess(
-				BuildName(iteratorVariableName, iterableToken.Line, iterableToken.Col),
-				"getNext",
-				[]*Token{},
-				iterableToken.Line,
-				iterableToken.Col,
-			),
-			iterableToken.Line,
-			iterableToken.Col,
-		),
+	getNext := BuildAccess(
+		BuildName(iteratorVariableName, line, col),
+		"getNext",
+		[]*Token{},
+		line,
+		col,
+	)
+	// The loop variables are declared inside the loop body, so each
+	// iteration gets fresh bindings and closures created in one
+	// iteration don't see the values of later ones
+	newBlockChildren := []*Token{}
+	if len(loopVariableTokens) == 1 {
+		newBlockChildren = append(newBlockChildren, BuildDeclaration(
+			BuildName(firstVariableName, loopVariableTokens[0].Line, loopVariableTokens[0].Col),
+			getNext,
+			loopVariableTokens[0].Line,
+			loopVariableTokens[0].Col,
+		))
+	} else {
+		entryVariableName := "~" + firstVariableName + "Entry"
+		newBlockChildren = append(newBlockChildren, BuildDeclaration(
+			BuildName(entryVariableName, line, col),
+			getNext,
+			line,
+			col,
+		))
+		for i, loopVariableToken := range loopVariableTokens {
+			newBlockChildren = append(newBlockChildren, BuildDeclaration(
+				BuildName(loopVariableToken.Value, loopVariableToken.Line, loopVariableToken.Col),
+				BuildIndex(BuildName(entryVariableName, line, col), BuildIntLiteral(i, line, col), line, col),
+				loopVariableToken.Line,
+				loopVariableToken.Col,
+			))
+		}
 	}
-	newBlockChildren = append(newBlockChildren, originalBlockToken.Children...)
+	// The original body stays a block of its own, so it can declare
+	// names, including ones that shadow the loop variables
+	newBlockChildren = append(newBlockChildren, originalBlockToken)
 
 	newBlock := BuildBlock(newBlockChildren, originalBlockToken.Line, originalBlockToken.Col)
 
@@ -86,6 +121,19 @@ func UnsweetenForIn(tree *Token) (*Token, exception.Exception) {
 		tree.Line,
 		tree.Col,
 	)
-	unsweetenedTree := BuildBlock([]*Token{iterationVariableInitalizer, iteratorInitializer, whileStatement}, tree.Line, tree.Col)
+	unsweetenedTree := BuildBlock(append(declarations, whileStatement), tree.Line, tree.Col)
 	return unsweetenedTree, nil
 }
+
+// Moves a label onto the while loop it labels, as the loop's optional
+// third child. Labeled for-in loops have already been unsweetened into
+// a block that ends with their while loop
+func UnsweetenLabeled(tree *Token) (*Token, exception.Exception) {
+	loop := tree.Children[0]
+	whileLoop := loop
+	if whileLoop.Symbol == Block {
+		whileLoop = whileLoop.Children[len(whileLoop.Children)-1]
+	}
+	whileLoop.Children = append(whileLoop.Children, BuildName(tree.Value, tree.Line, tree.Col))
+	return loop, nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/interpreter"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+const (
+	replPrompt             = "otter> "
+	replContinuationPrompt = "...    "
+	replHistoryFileName    = ".otter_history"
+)
+
+// A Repl is an interactive read-eval-print loop. A single
+// interpreter is kept alive for the whole session, so definitions
+// made in one input are visible to the next.
+type Repl struct {
+	engine      *interpreter.Engine
+	language    *parser.LanguageSpecification
+	input       *bufio.Scanner
+	output      io.Writer
+	history     []string
+	historyPath string
+}
+
+func NewRepl(input io.Reader, output io.Writer) *Repl {
+	historyPath := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyPath = filepath.Join(home, replHistoryFileName)
+	}
+	repl := &Repl{
+		engine:      interpreter.NewEngine(),
+		language:    parser.NewOtterLanguage(),
+		input:       bufio.NewScanner(input),
+		output:      output,
+		history:     []string{},
+		historyPath: historyPath,
+	}
+	repl.engine.Interpreter.CallStack.Globals().FileName = "<repl>"
+	repl.engine.Interpreter.Incremental = true
+	repl.loadHistory()
+	return repl
+}
+
+// Runs the loop until the input is exhausted or the user
+// enters :quit
+func (repl *Repl) Run() {
+	fmt.Fprintln(repl.output, "Otter REPL. Type :help for help, :quit to exit.")
+	var buffer strings.Builder
+	for {
+		if buffer.Len() == 0 {
+			fmt.Fprint(repl.output, replPrompt)
+		} else {
+			fmt.Fprint(repl.output, replContinuationPrompt)
+		}
+		if !repl.input.Scan() {
+			fmt.Fprintln(repl.output)
+			return
+		}
+		line := repl.input.Text()
+
+		if buffer.Len() == 0 {
+			command := strings.TrimSpace(line)
+			if command == "" {
+				continue
+			}
+			if strings.HasPrefix(command, ":") {
+				if !repl.runCommand(command) {
+					return
+				}
+				continue
+			}
+		}
+
+		buffer.WriteString(line)
+		buffer.WriteString("\n")
+
+		source, complete, err := repl.completeInput(buffer.String())
+		if err != nil {
+			fmt.Fprintf(repl.output, "%v\n", err)
+			buffer.Reset()
+			continue
+		}
+		if !complete {
+			continue
+		}
+		buffer.Reset()
+		repl.addHistory(strings.TrimSpace(source))
+		repl.evaluate(source)
+	}
+}
+
+// Handles a REPL command. Returns false if the session should end
+func (repl *Repl) runCommand(command string) bool {
+	switch command {
+	case ":quit", ":q", ":exit":
+		return false
+	case ":history":
+		for index, entry := range repl.history {
+			fmt.Fprintf(repl.output, "%5d  %v\n", index+1, entry)
+		}
+	case ":help":
+		fmt.Fprintln(repl.output, ":help     show this message")
+		fmt.Fprintln(repl.output, ":history  show previous inputs")
+		fmt.Fprintln(repl.output, ":quit     exit the REPL")
+	default:
+		fmt.Fprintf(repl.output, "unknown command %v\n", command)
+	}
+	return true
+}
+
+// Determines whether the buffered source forms a complete input by
+// checking that every block and parenthesis opened in the token stream
+// has been closed. A missing statement terminator on the final statement
+// is supplied so that expressions can be typed without a trailing ';'
+func (repl *Repl) completeInput(source string) (string, bool, error) {
+	lexer := parser.NewLexer(strings.NewReader(source), repl.language)
+	depth := 0
+	var last *parser.Token
+	for {
+		token, err := lexer.Next()
+		if err != nil {
+			return "", false, err
+		}
+		if token.Symbol == parser.EOF {
+			break
+		}
+		if lexer.IsBlockStart(token) || token.Symbol == "(" || token.Symbol == "[" {
+			depth++
+		} else if lexer.IsAnyBlockEnd(token) || token.Symbol == ")" || token.Symbol == "]" {
+			depth--
+		}
+		last = token
+	}
+	if depth > 0 {
+		return source, false, nil
+	}
+	if last == nil {
+		return source, true, nil
+	}
+	if lexer.IsStatementTerminator(last) {
+		return source, true, nil
+	}
+	// A closing brace usually ends a block, which needs no terminator,
+	// but it may also end a map literal, which does
+	if lexer.IsAnyBlockEnd(last) && repl.parses(source) {
+		return source, true, nil
+	}
+	return strings.TrimRight(source, "\n") + ";\n", true, nil
+}
+
+func (repl *Repl) parses(source string) bool {
+	_, err := repl.engine.ParserFactory(strings.NewReader(source)).Statements()
+	return err == nil
+}
+
+func (repl *Repl) evaluate(source string) {
+	statements, err := repl.engine.ParserFactory(strings.NewReader(source)).Statements()
+	if err != nil {
+		fmt.Fprintf(repl.output, "%v\n", err)
+		return
+	}
+	for _, statement := range statements {
+		value, err := repl.engine.Interpreter.Execute([]*parser.Token{statement})
+		if err != nil {
+			fmt.Fprintf(repl.output, "%v\n", exception.Format(err))
+			return
+		}
+		if statement.Symbol == parser.FunctionDefinition || isNullValue(value) {
+			continue
+		}
+		fmt.Fprintln(repl.output, repl.display(value))
+	}
+}
+
+// Null values are not echoed, which keeps calls like print from
+// producing noise. Null is the only value with neither an underlying
+// Go value nor a callable
+func isNullValue(value *interpreter.OtterValue) bool {
+	return value == nil || (value.Value == nil && value.Callable == nil)
+}
+
+func (repl *Repl) display(value *interpreter.OtterValue) string {
+	if value.IsInstanceOf(interpreter.TString) {
+		return strconv.Quote(value.Value.(string))
+	}
+	asString, err := interpreter.ConstructString(repl.engine.Interpreter, []*interpreter.OtterValue{value})
+	if err != nil {
+		return value.String()
+	}
+	return asString.Value.(string)
+}
+
+func (repl *Repl) loadHistory() {
+	if repl.historyPath == "" {
+		return
+	}
+	file, err := os.Open(repl.historyPath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Multi-line entries are stored with escaped newlines
+		entry, err := strconv.Unquote(scanner.Text())
+		if err != nil {
+			continue
+		}
+		repl.history = append(repl.history, entry)
+	}
+}
+
+func (repl *Repl) addHistory(entry string) {
+	repl.history = append(repl.history, entry)
+	if repl.historyPath == "" {
+		return
+	}
+	file, err := os.OpenFile(repl.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, strconv.Quote(entry))
+}
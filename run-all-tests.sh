@@ -1,6 +1,8 @@
 go build
-for file in ./test_scripts/*
+for file in ./test_scripts/*.otter
 do
     echo "Running tests in $file"
     ./otter $file
-done
\ No newline at end of file
+    echo "Running tests in $file with the bytecode virtual machine"
+    ./otter --vm $file
+done
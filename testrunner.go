@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nicholasbailey/otter/exception"
+	"github.com/nicholasbailey/otter/interpreter"
+	"github.com/nicholasbailey/otter/parser"
+)
+
+const (
+	testFunctionPrefix = "test_"
+	testFilePrefix     = "test_"
+	otterFileExtension = ".otter"
+)
+
+// A testCase is a single test_ function defined at the top level
+// of a test file, and the outcome of running it. A file that defines
+// no tests, or that can't be run, is itself a testCase
+type testCase struct {
+	fileName string
+	name     string
+	// Whether the test stands for a whole file
+	file     bool
+	line     int
+	col      int
+	err      error
+	duration time.Duration
+}
+
+func (test *testCase) passed() bool {
+	return test.err == nil
+}
+
+// Failed assertions are failures, and any other exception is an
+// error in the test itself. Exceptions raised by a file's top level
+// code are always errors
+func (test *testCase) failed() bool {
+	return test.err != nil && !test.file && exception.TypeOf(test.err) == exception.AssertionError
+}
+
+// A testRunner runs the Otter files in a set and the test_ functions
+// they define. Each file's top level code runs first, and runs again
+// on a fresh interpreter before every test after the first, so tests
+// can't affect each other. A failing test doesn't stop the rest running.
+type testRunner struct {
+	output   io.Writer
+	strict   bool
+	bytecode bool
+	tests    []*testCase
+}
+
+// Runs otter test with the arguments following "test", returning
+// the process exit code
+func runTestCommand(arguments []string, strict bool, bytecode bool) int {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	junitPath := flags.String("junit", "", "also write the results as JUnit XML to this file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: otter test [-junit file] [path ...]")
+		fmt.Fprintln(flags.Output(), "Runs the test_ functions in the given .otter files, or in the test_*.otter files under the given directories")
+		flags.PrintDefaults()
+	}
+	flags.Parse(arguments)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	fileNames, err := findTestFiles(paths)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return 1
+	}
+
+	runner := &testRunner{output: os.Stdout, strict: strict, bytecode: bytecode}
+	for _, fileName := range fileNames {
+		runner.runFile(fileName)
+	}
+	passed := runner.report()
+
+	if *junitPath != "" {
+		if err := runner.writeJUnit(*junitPath); err != nil {
+			fmt.Printf("%v\n", err)
+			return 1
+		}
+	}
+	if !passed {
+		return 1
+	}
+	return 0
+}
+
+// Expands directories in paths to the test_*.otter files beneath
+// them. Files named explicitly are always included
+func findTestFiles(paths []string) ([]string, error) {
+	fileNames := []string{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			fileNames = append(fileNames, path)
+			continue
+		}
+		err = filepath.Walk(path, func(fileName string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			baseName := filepath.Base(fileName)
+			if !info.IsDir() && strings.HasPrefix(baseName, testFilePrefix) && strings.HasSuffix(baseName, otterFileExtension) {
+				fileNames = append(fileNames, fileName)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fileNames, nil
+}
+
+// Runs a file and every test in it. A file that defines no tests is
+// reported as a single test named after the file, as is a file that
+// can't be read or parsed or whose top level code raises an exception
+func (runner *testRunner) runFile(fileName string) {
+	start := time.Now()
+	source, err := os.ReadFile(fileName)
+	if err == nil {
+		var statements []*parser.Token
+		statements, err = parser.NewParser(bytes.NewReader(source)).Statements()
+		if err == nil {
+			var engine *interpreter.Engine
+			engine, err = runner.load(fileName, source)
+			if err == nil && runner.runStatements(engine, fileName, source, statements) > 0 {
+				return
+			}
+		}
+	}
+	test := &testCase{
+		fileName: fileName,
+		name:     filepath.Base(fileName),
+		file:     true,
+		duration: time.Since(start),
+	}
+	if err != nil {
+		test.err = exception.WithFileName(err, fileName)
+	}
+	runner.addTest(test)
+}
+
+// Runs the tests defined by the top level statements of a file,
+// returning how many there were. The first test runs on the engine
+// that loaded the file, and each later test on a fresh one
+func (runner *testRunner) runStatements(engine *interpreter.Engine, fileName string, source []byte, statements []*parser.Token) int {
+	count := 0
+	for _, statement := range statements {
+		if statement.Symbol != parser.FunctionDefinition {
+			continue
+		}
+		name := statement.Children[0].Value
+		if !strings.HasPrefix(name, testFunctionPrefix) {
+			continue
+		}
+		test := &testCase{
+			fileName: fileName,
+			name:     name,
+			line:     statement.Line,
+			col:      statement.Col,
+		}
+		start := time.Now()
+		if engine == nil {
+			engine, test.err = runner.load(fileName, source)
+		}
+		if test.err == nil {
+			test.err = runTest(engine, test)
+		}
+		engine = nil
+		test.duration = time.Since(start)
+		runner.addTest(test)
+		count++
+	}
+	return count
+}
+
+// Creates an engine and runs a file's top level code on it
+func (runner *testRunner) load(fileName string, source []byte) (*interpreter.Engine, error) {
+	engine := interpreter.NewEngine()
+	engine.Interpreter.Strict = runner.strict
+	engine.Interpreter.Bytecode = runner.bytecode
+	if _, err := engine.ExecuteFile(fileName, bytes.NewReader(source)); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+func runTest(engine *interpreter.Engine, test *testCase) error {
+	function, found := engine.Interpreter.CallStack.Globals().Environment.Resolve(test.name)
+	if !found {
+		return exception.New(exception.NameError, fmt.Sprintf("%v is not defined", test.name), test.line, test.col)
+	}
+	if _, err := engine.Interpreter.Call(function, []*interpreter.OtterValue{}, test.line, test.col); err != nil {
+		return exception.WithFileName(err, test.fileName)
+	}
+	return nil
+}
+
+func (runner *testRunner) addTest(test *testCase) {
+	runner.tests = append(runner.tests, test)
+	runner.reportTest(test)
+}
+
+func (runner *testRunner) reportTest(test *testCase) {
+	status := "PASS"
+	if test.failed() {
+		status = "FAIL"
+	} else if !test.passed() {
+		status = "ERROR"
+	}
+	fmt.Fprintf(runner.output, "%-5v %v:%v %v (%v)\n", status, test.fileName, test.line, test.name, test.duration.Round(time.Microsecond))
+	if !test.passed() {
+		fmt.Fprintf(runner.output, "%v\n", indent(exception.Format(test.err)))
+	}
+}
+
+// Prints the number of passing, failing and erroring tests.
+// Returns true if every test passed
+func (runner *testRunner) report() bool {
+	passed, failed, errored := runner.counts()
+	fmt.Fprintf(runner.output, "\n%v tests, %v passed, %v failed, %v errors\n", len(runner.tests), passed, failed, errored)
+	return failed == 0 && errored == 0
+}
+
+func (runner *testRunner) counts() (passed int, failed int, errored int) {
+	for _, test := range runner.tests {
+		if test.passed() {
+			passed++
+		} else if test.failed() {
+			failed++
+		} else {
+			errored++
+		}
+	}
+	return passed, failed, errored
+}
+
+func indent(text string) string {
+	return "    " + strings.ReplaceAll(text, "\n", "\n    ")
+}
+
+// The JUnit XML report format, as read by most CI servers. Each
+// file is a test suite
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+func (runner *testRunner) writeJUnit(path string) error {
+	report := &junitTestSuites{}
+	suites := map[string]*junitTestSuite{}
+	var total time.Duration
+	durations := map[string]time.Duration{}
+	for _, test := range runner.tests {
+		suite, found := suites[test.fileName]
+		if !found {
+			suite = &junitTestSuite{Name: test.fileName}
+			suites[test.fileName] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+		testCase := &junitTestCase{
+			Name:      test.name,
+			ClassName: strings.TrimSuffix(test.fileName, otterFileExtension),
+			File:      test.fileName,
+			Line:      test.line,
+			Time:      junitSeconds(test.duration),
+		}
+		if !test.passed() {
+			failure := &junitFailure{
+				Message: test.err.Error(),
+				Type:    string(exception.TypeOf(test.err)),
+				Details: exception.Format(test.err),
+			}
+			if test.failed() {
+				testCase.Failure = failure
+				suite.Failures++
+			} else {
+				testCase.Error = failure
+				suite.Errors++
+			}
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+		durations[test.fileName] += test.duration
+		total += test.duration
+	}
+	sort.SliceStable(report.Suites, func(i, j int) bool {
+		return report.Suites[i].Name < report.Suites[j].Name
+	})
+	for _, suite := range report.Suites {
+		suite.Time = junitSeconds(durations[suite.Name])
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+	}
+	report.Time = junitSeconds(total)
+
+	encoded, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	contents := append([]byte(xml.Header), encoded...)
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}